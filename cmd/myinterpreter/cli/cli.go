@@ -1,30 +1,51 @@
 package cli
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 type OperationType string
 
 const (
-	Parse    = "parse"
-	Tokenize = "tokenize"
-	Eval     = "evaluate"
-	Run      = "run"
+	Parse     = "parse"
+	Tokenize  = "tokenize"
+	Eval      = "evaluate"
+	Run       = "run"
+	TypeCheck = "typecheck"
 )
 
+// NoChecksFlag disables assert statements and function contracts.
+const NoChecksFlag = "--no-checks"
+
 type ParsedArgs struct {
 	Type     OperationType
 	FilePath string
+	NoChecks bool
 }
 
 func ParseArgs() (*ParsedArgs, error) {
 	if len(os.Args) < 3 {
 		return nil, WrongAmountOfArgsError
 	}
-	commandType := os.Args[1]
-	filePath := os.Args[2]
-
-	return &ParsedArgs{
-		Type:     OperationType(commandType),
-		FilePath: filePath,
-	}, nil
+	parsed := &ParsedArgs{
+		Type: OperationType(os.Args[1]),
+	}
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == NoChecksFlag:
+			parsed.NoChecks = true
+		case strings.HasPrefix(arg, "--"):
+			return nil, fmt.Errorf("%w: %s", UnknownFlagError, arg)
+		case parsed.FilePath != "":
+			return nil, WrongAmountOfArgsError
+		default:
+			parsed.FilePath = arg
+		}
+	}
+	if parsed.FilePath == "" {
+		return nil, WrongAmountOfArgsError
+	}
+	return parsed, nil
 }
@@ -3,3 +3,5 @@ package cli
 import "errors"
 
 var WrongAmountOfArgsError = errors.New("Error: wrong amount of args provided")
+
+var UnknownFlagError = errors.New("Error: unknown flag")
@@ -2,34 +2,45 @@ package environment
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
 )
 
+// Environment is shared between spawned tasks, so access is guarded by mu.
 type Environment struct {
+	mu        sync.RWMutex
 	enclosing *Environment
 	variables map[string]any
 }
 
 func (e *Environment) Define(name string, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.variables[name] = value
 }
 
 func (e *Environment) Assign(name *token.Token, value any) error {
+	e.mu.Lock()
 	_, ok := e.variables[name.Text]
+	if ok {
+		e.variables[name.Text] = value
+	}
+	e.mu.Unlock()
 	if !ok {
 		if e.enclosing != nil {
 			return e.enclosing.Assign(name, value)
 		}
 		return errors.NewRuntimeError(name, fmt.Sprintf("Undefined variable '%s'.", name.Text))
 	}
-	e.variables[name.Text] = value
 	return nil
 }
 
 func (e *Environment) Get(name *token.Token) (any, error) {
+	e.mu.RLock()
 	v, ok := e.variables[name.Text]
+	e.mu.RUnlock()
 
 	if !ok {
 		if e.enclosing != nil {
@@ -0,0 +1,47 @@
+package expression
+
+import "github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+
+// ComprehensionExpression is `#{x * 2 for x in xs if x > 0}`, Brace is the
+// opening token and decides the collection that is built.
+type ComprehensionExpression struct {
+	Brace   *token.Token
+	Element Expression
+	Clauses []*ComprehensionClause
+}
+
+func (this *ComprehensionExpression) Accept(v Visitor) {
+	v.VisitComprehensionExpression(this)
+}
+
+// ComprehensionClause is either `for a, b in iterable` or `if condition`,
+// told apart by Keywoard.
+type ComprehensionClause struct {
+	Keywoard  *token.Token
+	Names     []*token.Token
+	Iterable  Expression
+	Condition Expression
+}
+
+func NewComprehensionExpression(brace *token.Token, element Expression, clauses []*ComprehensionClause) *ComprehensionExpression {
+	return &ComprehensionExpression{
+		Brace:   brace,
+		Element: element,
+		Clauses: clauses,
+	}
+}
+
+func NewForClause(keywoard *token.Token, names []*token.Token, iterable Expression) *ComprehensionClause {
+	return &ComprehensionClause{
+		Keywoard: keywoard,
+		Names:    names,
+		Iterable: iterable,
+	}
+}
+
+func NewIfClause(keywoard *token.Token, condition Expression) *ComprehensionClause {
+	return &ComprehensionClause{
+		Keywoard:  keywoard,
+		Condition: condition,
+	}
+}
@@ -11,6 +11,16 @@ type Visitor interface {
 	VisitAssignmentExpression(u *AssignmentExpression)
 	VisitLogicalExpression(u *LogicalExpression)
 	VisitFunctionCallExpression(u *FunctionCallExpression)
+	VisitMatchExpression(u *MatchExpression)
+	VisitGetExpression(u *GetExpression)
+	VisitIndexExpression(u *IndexExpression)
+	VisitSetExpression(u *SetExpression)
+	VisitTupleExpression(u *TupleExpression)
+	VisitComprehensionExpression(u *ComprehensionExpression)
+	VisitPipelineExpression(u *PipelineExpression)
+	VisitMacroCallExpression(u *MacroCallExpression)
+	VisitQuoteExpression(u *QuoteExpression)
+	VisitUnquoteExpression(u *UnquoteExpression)
 }
 
 type Expression interface {
@@ -51,10 +61,90 @@ type LogicalExpression struct {
 	Rhs Expression
 }
 
+// PipelineExpression is `lhs |> rhs`, lhs becomes the first argument of
+// the call rhs, or the only argument when rhs isn't a call.
+type PipelineExpression struct {
+	Lhs Expression
+	Op  *token.Token
+	Rhs Expression
+}
+
+func (this *PipelineExpression) Accept(v Visitor) {
+	v.VisitPipelineExpression(this)
+}
+
 type FunctionCallExpression struct {
 	Callee      Expression
 	Args       []Expression
 	RightParan *token.Token
+	// Guarded calls follow an optional link of the same chain and are
+	// skipped when that link short-circuits.
+	Guarded bool
+}
+
+// GetExpression is `object.name`, or `object?.name` when Optional, which
+// is nil instead of an error when object is nil.
+type GetExpression struct {
+	Object   Expression
+	Name     *token.Token
+	Optional bool
+	Guarded  bool
+}
+
+func (this *GetExpression) Accept(v Visitor) {
+	v.VisitGetExpression(this)
+}
+
+// IndexExpression is `object[index]`, or `object?[index]` when Optional,
+// Bracket is kept for error lines.
+type IndexExpression struct {
+	Object   Expression
+	Bracket  *token.Token
+	Index    Expression
+	Optional bool
+	Guarded  bool
+}
+
+func (this *IndexExpression) Accept(v Visitor) {
+	v.VisitIndexExpression(this)
+}
+
+// SetExpression is a `#{a, b}` literal.
+type SetExpression struct {
+	Brace    *token.Token
+	Elements []Expression
+}
+
+func (this *SetExpression) Accept(v Visitor) {
+	v.VisitSetExpression(this)
+}
+
+// TupleExpression is `(a, b)`, a parenthesized expression becomes a tuple
+// once it has a comma. Paren is the opening token, or the return keyword
+// for `return a, b;`.
+type TupleExpression struct {
+	Paren    *token.Token
+	Elements []Expression
+}
+
+func (this *TupleExpression) Accept(v Visitor) {
+	v.VisitTupleExpression(this)
+}
+
+type MatchExpression struct {
+	Keywoard *token.Token
+	Value    Expression
+	Arms     []*MatchArm
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression
+	Body    Expression
+}
+
+func (this *MatchExpression) Accept(v Visitor) {
+	v.VisitMatchExpression(this)
 }
 
 func (this *FunctionCallExpression) Accept(v Visitor) {
@@ -153,10 +243,73 @@ func NewFunctionCallExpression(
     callee Expression,
     args []Expression,
     rightParan *token.Token,
+    guarded bool,
 ) *FunctionCallExpression {
     return &FunctionCallExpression{
         Callee:      callee,
         Args:       args,
         RightParan: rightParan,
+        Guarded:    guarded,
     }
 }
+
+func NewMatchExpression(
+	keywoard *token.Token,
+	value Expression,
+	arms []*MatchArm,
+) *MatchExpression {
+	return &MatchExpression{
+		Keywoard: keywoard,
+		Value:    value,
+		Arms:     arms,
+	}
+}
+
+func NewMatchArm(pattern Pattern, guard Expression, body Expression) *MatchArm {
+	return &MatchArm{
+		Pattern: pattern,
+		Guard:   guard,
+		Body:    body,
+	}
+}
+
+func NewGetExpression(object Expression, name *token.Token, optional bool, guarded bool) *GetExpression {
+	return &GetExpression{
+		Object:   object,
+		Name:     name,
+		Optional: optional,
+		Guarded:  guarded,
+	}
+}
+
+func NewIndexExpression(object Expression, bracket *token.Token, index Expression, optional bool, guarded bool) *IndexExpression {
+	return &IndexExpression{
+		Object:   object,
+		Bracket:  bracket,
+		Index:    index,
+		Optional: optional,
+		Guarded:  guarded,
+	}
+}
+
+func NewSetExpression(brace *token.Token, elements []Expression) *SetExpression {
+	return &SetExpression{
+		Brace:    brace,
+		Elements: elements,
+	}
+}
+
+func NewTupleExpression(paren *token.Token, elements []Expression) *TupleExpression {
+	return &TupleExpression{
+		Paren:    paren,
+		Elements: elements,
+	}
+}
+
+func NewPipelineExpression(lhs Expression, op *token.Token, rhs Expression) *PipelineExpression {
+	return &PipelineExpression{
+		Lhs: lhs,
+		Op:  op,
+		Rhs: rhs,
+	}
+}
@@ -0,0 +1,72 @@
+package expression
+
+import "github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+
+// MacroCallExpression is `name!(arg, ...)`. It never runs, the macro pass
+// replaces it with the expanded template before interpretation.
+type MacroCallExpression struct {
+	Name *token.Token
+	Args []*MacroArg
+}
+
+func (this *MacroCallExpression) Accept(v Visitor) {
+	v.VisitMacroCallExpression(this)
+}
+
+// MacroArg is an unevaluated argument, either an expression or a block of
+// statements. Block holds a *stmt.BlockStmt, stmt imports this package so
+// the type can't be named here.
+type MacroArg struct {
+	Exp   Expression
+	Block any
+}
+
+func NewMacroCallExpression(name *token.Token, args []*MacroArg) *MacroCallExpression {
+	return &MacroCallExpression{
+		Name: name,
+		Args: args,
+	}
+}
+
+// QuoteExpression is `quote(exp)` or `quote { statements }`, its value is
+// the quoted code. Unquotes are the unquote expressions of the quoted code
+// that belong to this quote and not to one nested in it, they are
+// evaluated when the quote is.
+type QuoteExpression struct {
+	Keywoard *token.Token
+	Exp      Expression
+	// Block holds a *stmt.BlockStmt, like MacroArg.Block.
+	Block    any
+	Unquotes []*UnquoteExpression
+}
+
+func (this *QuoteExpression) Accept(v Visitor) {
+	v.VisitQuoteExpression(this)
+}
+
+// UnquoteExpression is `unquote(exp)` in quoted code, the value of exp is
+// spliced into the code in its place.
+type UnquoteExpression struct {
+	Keywoard *token.Token
+	Exp      Expression
+}
+
+func (this *UnquoteExpression) Accept(v Visitor) {
+	v.VisitUnquoteExpression(this)
+}
+
+func NewQuoteExpression(keywoard *token.Token, exp Expression, block any, unquotes []*UnquoteExpression) *QuoteExpression {
+	return &QuoteExpression{
+		Keywoard: keywoard,
+		Exp:      exp,
+		Block:    block,
+		Unquotes: unquotes,
+	}
+}
+
+func NewUnquoteExpression(keywoard *token.Token, exp Expression) *UnquoteExpression {
+	return &UnquoteExpression{
+		Keywoard: keywoard,
+		Exp:      exp,
+	}
+}
@@ -0,0 +1,41 @@
+package expression
+
+import "github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+
+type Pattern interface {
+	pattern()
+}
+
+type ValuePattern struct {
+	Exp Expression
+}
+
+type BindingPattern struct {
+	Name *token.Token
+}
+
+type WildcardPattern struct {
+	Token *token.Token
+}
+
+func (*ValuePattern) pattern()    {}
+func (*BindingPattern) pattern()  {}
+func (*WildcardPattern) pattern() {}
+
+func NewValuePattern(exp Expression) *ValuePattern {
+	return &ValuePattern{
+		Exp: exp,
+	}
+}
+
+func NewBindingPattern(name *token.Token) *BindingPattern {
+	return &BindingPattern{
+		Name: name,
+	}
+}
+
+func NewWildcardPattern(t *token.Token) *WildcardPattern {
+	return &WildcardPattern{
+		Token: t,
+	}
+}
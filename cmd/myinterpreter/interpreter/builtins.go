@@ -0,0 +1,188 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// NativeMethod is a method of a built-in type implemented in Go, this is
+// the receiver the method was looked up on.
+type NativeMethod struct {
+	name  string
+	arity int
+	fn    func(interp *Interpreter, this any, args []any) (any, error)
+}
+
+// BoundMethod is a NativeMethod bound to its receiver, it is what
+// `"abc".upper` evaluates to.
+type BoundMethod struct {
+	this   any
+	method *NativeMethod
+}
+
+func (b *BoundMethod) Call(interp *Interpreter, args []any) (any, error) {
+	return b.method.fn(interp, b.this, args)
+}
+
+func (b BoundMethod) Arity() int {
+	return b.method.arity
+}
+
+func (b BoundMethod) String() string {
+	return "<native fn>"
+}
+
+func NewBoundMethod(this any, method *NativeMethod) *BoundMethod {
+	return &BoundMethod{
+		this:   this,
+		method: method,
+	}
+}
+
+// builtinMethods returns the method table for the type of v, or nil if
+// values of that type have no methods.
+func builtinMethods(v any) map[string]*NativeMethod {
+	switch v.(type) {
+	case string:
+		return stringMethods
+	case float64:
+		return numberMethods
+	case *Range:
+		return rangeMethods
+	case *Set:
+		return setMethods
+	case *Channel:
+		return channelMethods
+	case *File:
+		return fileMethods
+	case *Bytes:
+		return bytesMethods
+	}
+	return nil
+}
+
+func stringMethod(name string, arity int, fn func(this string, args []any) (any, error)) *NativeMethod {
+	return &NativeMethod{
+		name:  name,
+		arity: arity,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return fn(this.(string), args)
+		},
+	}
+}
+
+func numberMethod(name string, fn func(this float64) float64) *NativeMethod {
+	return &NativeMethod{
+		name:  name,
+		arity: 0,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return fn(this.(float64)), nil
+		},
+	}
+}
+
+var stringMethods = map[string]*NativeMethod{
+	"length": stringMethod("length", 0, func(this string, args []any) (any, error) {
+		return float64(len([]rune(this))), nil
+	}),
+	"upper": stringMethod("upper", 0, func(this string, args []any) (any, error) {
+		return strings.ToUpper(this), nil
+	}),
+	"lower": stringMethod("lower", 0, func(this string, args []any) (any, error) {
+		return strings.ToLower(this), nil
+	}),
+	"trim": stringMethod("trim", 0, func(this string, args []any) (any, error) {
+		return strings.TrimSpace(this), nil
+	}),
+	"contains": stringMethod("contains", 1, func(this string, args []any) (any, error) {
+		sub, err := stringArg("contains", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.Contains(this, sub), nil
+	}),
+	"startsWith": stringMethod("startsWith", 1, func(this string, args []any) (any, error) {
+		prefix, err := stringArg("startsWith", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasPrefix(this, prefix), nil
+	}),
+	"endsWith": stringMethod("endsWith", 1, func(this string, args []any) (any, error) {
+		suffix, err := stringArg("endsWith", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasSuffix(this, suffix), nil
+	}),
+	"indexOf": stringMethod("indexOf", 1, func(this string, args []any) (any, error) {
+		sub, err := stringArg("indexOf", args[0])
+		if err != nil {
+			return nil, err
+		}
+		idx := strings.Index(this, sub)
+		if idx < 0 {
+			return float64(-1), nil
+		}
+		return float64(len([]rune(this[:idx]))), nil
+	}),
+	"replace": stringMethod("replace", 2, func(this string, args []any) (any, error) {
+		old, err := stringArg("replace", args[0])
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := stringArg("replace", args[1])
+		if err != nil {
+			return nil, err
+		}
+		return strings.ReplaceAll(this, old, replacement), nil
+	}),
+	"repeat": stringMethod("repeat", 1, func(this string, args []any) (any, error) {
+		count, err := indexArg("repeat", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.Repeat(this, count), nil
+	}),
+	"substring": stringMethod("substring", 2, func(this string, args []any) (any, error) {
+		start, err := indexArg("substring", args[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := indexArg("substring", args[1])
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(this)
+		if start > end || end > len(runes) {
+			return nil, fmt.Errorf("Substring range [%v, %v) is out of bounds for length %v.", start, end, len(runes))
+		}
+		return string(runes[start:end]), nil
+	}),
+}
+
+var numberMethods = map[string]*NativeMethod{
+	"abs":   numberMethod("abs", math.Abs),
+	"floor": numberMethod("floor", math.Floor),
+	"ceil":  numberMethod("ceil", math.Ceil),
+	"round": numberMethod("round", math.Round),
+	"sqrt":  numberMethod("sqrt", math.Sqrt),
+}
+
+func stringArg(method string, v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("Argument to '%s' must be a string.", method)
+	}
+	return s, nil
+}
+
+// indexArg accepts non-negative whole numbers, like counts and indices.
+func indexArg(method string, v any) (int, error) {
+	n, ok := v.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return 0, fmt.Errorf("Argument to '%s' must be a non-negative integer.", method)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,277 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+)
+
+// Bytes is a mutable buffer of binary data, its elements are numbers from
+// 0 to 255.
+type Bytes struct {
+	mu   sync.RWMutex
+	data []byte
+}
+
+// snapshot returns a copy of the data, so callers can keep it while the
+// buffer is modified.
+func (b *Bytes) snapshot() []byte {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]byte{}, b.data...)
+}
+
+func (b *Bytes) length() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.data)
+}
+
+func (b *Bytes) set(idx int, v byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if idx >= len(b.data) {
+		return fmt.Errorf("Bytes index %v is out of bounds for length %v.", idx, len(b.data))
+	}
+	b.data[idx] = v
+	return nil
+}
+
+func (b *Bytes) append(data ...byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, data...)
+}
+
+func (b *Bytes) Iterator() Iterator {
+	data := b.snapshot()
+	values := make([]any, len(data))
+	for i, v := range data {
+		values[i] = float64(v)
+	}
+	return &sliceIterator{values: values}
+}
+
+// String prints the buffer as a literal, bytes that aren't printable
+// ASCII are escaped.
+func (b *Bytes) String() string {
+	var s strings.Builder
+	s.WriteString(`b"`)
+	for _, c := range b.snapshot() {
+		switch {
+		case c == '"' || c == '\\':
+			s.WriteByte('\\')
+			s.WriteByte(c)
+		case c >= 0x20 && c < 0x7f:
+			s.WriteByte(c)
+		default:
+			fmt.Fprintf(&s, "\\x%02x", c)
+		}
+	}
+	s.WriteString(`"`)
+	return s.String()
+}
+
+func NewBytes(data []byte) *Bytes {
+	return &Bytes{
+		data: data,
+	}
+}
+
+func concatBytes(a *Bytes, b *Bytes) *Bytes {
+	return NewBytes(append(a.snapshot(), b.snapshot()...))
+}
+
+// indexBytes returns the byte as a number or a copy of the bytes picked
+// out by index.
+func indexBytes(b *Bytes, index any) (any, error) {
+	data := b.snapshot()
+	positions, single, err := indexPositions("Bytes", index, len(data))
+	if err != nil {
+		return nil, err
+	}
+	if single {
+		return float64(data[positions[0]]), nil
+	}
+	out := make([]byte, len(positions))
+	for i, idx := range positions {
+		out[i] = data[idx]
+	}
+	return NewBytes(out), nil
+}
+
+// containsBytes implements `value in b` for a byte or a run of bytes.
+func containsBytes(b *Bytes, value any) (bool, error) {
+	switch t := value.(type) {
+	case float64:
+		v, err := byteArg(t)
+		return err == nil && bytes.IndexByte(b.snapshot(), v) >= 0, nil
+	case *Bytes:
+		return bytes.Contains(b.snapshot(), t.snapshot()), nil
+	}
+	return false, fmt.Errorf("Left operand of 'in' must be a number or bytes when searching bytes.")
+}
+
+func byteArg(v any) (byte, error) {
+	n, ok := v.(float64)
+	if !ok || n < 0 || n > 255 || n != math.Trunc(n) {
+		return 0, fmt.Errorf("Byte value must be an integer from 0 to 255.")
+	}
+	return byte(n), nil
+}
+
+func encoding(v any) (string, error) {
+	enc, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("Encoding must be a string.")
+	}
+	return enc, nil
+}
+
+func unknownEncoding(enc string) error {
+	return fmt.Errorf("Unknown encoding '%s', expected \"utf8\", \"hex\" or \"base64\".", enc)
+}
+
+func encode(s string, enc string) ([]byte, error) {
+	switch enc {
+	case "utf8":
+		return []byte(s), nil
+	case "hex":
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid hex string.")
+		}
+		return data, nil
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid base64 string.")
+		}
+		return data, nil
+	}
+	return nil, unknownEncoding(enc)
+}
+
+func decode(data []byte, enc string) (string, error) {
+	switch enc {
+	case "utf8":
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("Bytes are not valid utf8.")
+		}
+		return string(data), nil
+	case "hex":
+		return hex.EncodeToString(data), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	return "", unknownEncoding(enc)
+}
+
+func bytesMethod(name string, arity int, fn func(this *Bytes, args []any) (any, error)) *NativeMethod {
+	return &NativeMethod{
+		name:  name,
+		arity: arity,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return fn(this.(*Bytes), args)
+		},
+	}
+}
+
+var bytesMethods = map[string]*NativeMethod{
+	"length": bytesMethod("length", 0, func(this *Bytes, args []any) (any, error) {
+		return float64(this.length()), nil
+	}),
+	// decode takes the encoding, "utf8", "hex" or "base64". Unlike
+	// bytes(s) it has no default, the encoding is always spelled out.
+	"decode": bytesMethod("decode", 1, func(this *Bytes, args []any) (any, error) {
+		enc, err := encoding(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return decode(this.snapshot(), enc)
+	}),
+	"set": bytesMethod("set", 2, func(this *Bytes, args []any) (any, error) {
+		idx, err := indexArg("set", args[0])
+		if err != nil {
+			return nil, err
+		}
+		v, err := byteArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return nil, this.set(idx, v)
+	}),
+	// append takes a byte or other bytes.
+	"append": bytesMethod("append", 1, func(this *Bytes, args []any) (any, error) {
+		if other, ok := args[0].(*Bytes); ok {
+			this.append(other.snapshot()...)
+			return nil, nil
+		}
+		v, err := byteArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		this.append(v)
+		return nil, nil
+	}),
+}
+
+// nativeBytes is bytes(n) for n zero bytes, bytes(s) or bytes(s, encoding)
+// to encode a string, or bytes(iterable) to collect numbers.
+func nativeBytes(interp *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("Expected 1 to 2 arguments but got %v.", len(args))
+	}
+	if s, ok := args[0].(string); ok {
+		enc := "utf8"
+		if len(args) == 2 {
+			e, err := encoding(args[1])
+			if err != nil {
+				return nil, err
+			}
+			enc = e
+		}
+		data, err := encode(s, enc)
+		if err != nil {
+			return nil, err
+		}
+		return NewBytes(data), nil
+	}
+	if len(args) == 2 {
+		return nil, fmt.Errorf("Only strings can be passed to 'bytes' with an encoding.")
+	}
+	switch t := args[0].(type) {
+	case float64:
+		n, err := indexArg("bytes", t)
+		if err != nil {
+			return nil, err
+		}
+		return NewBytes(make([]byte, n)), nil
+	case *Bytes:
+		return NewBytes(t.snapshot()), nil
+	}
+	it, ok := iterate(args[0])
+	if !ok {
+		return nil, fmt.Errorf("Argument to 'bytes' must be a number, string or an iterable of numbers.")
+	}
+	data := []byte{}
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		b, err := byteArg(v)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+	}
+	return NewBytes(data), nil
+}
+
+func defineBytesGlobals(env *environment.Environment) {
+	env.Define("bytes", NewNativeFunction("bytes", variadicArity, nativeBytes))
+}
@@ -0,0 +1,226 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+)
+
+// Channel is an unbuffered channel. Its queues are guarded by the lock of
+// the task group it belongs to, so the group always knows which tasks are
+// blocked on a channel and can report a deadlock instead of hanging.
+type Channel struct {
+	tasks     *taskGroup
+	senders   []*waiter
+	receivers []*waiter
+	closed    bool
+}
+
+// waiter is a task blocked on one or more channels. Whoever completes its
+// operation fills in the result and closes done.
+type waiter struct {
+	value any
+	from  *Channel
+	err   error
+	fired bool
+	done  chan struct{}
+}
+
+func newWaiter() *waiter {
+	return &waiter{done: make(chan struct{})}
+}
+
+// pop removes and returns the first waiter of queue that has not been woken
+// already, waiters of a select stay queued on the channels that lost.
+func pop(queue *[]*waiter) *waiter {
+	for len(*queue) > 0 {
+		w := (*queue)[0]
+		*queue = (*queue)[1:]
+		if !w.fired {
+			return w
+		}
+	}
+	return nil
+}
+
+func (c *Channel) send(v any) error {
+	t := c.tasks
+	t.mu.Lock()
+	if c.closed {
+		t.mu.Unlock()
+		return fmt.Errorf("Send on closed channel.")
+	}
+	if r := pop(&c.receivers); r != nil {
+		t.fire(r, c, v, nil)
+		t.mu.Unlock()
+		return nil
+	}
+	w := newWaiter()
+	w.value = v
+	c.senders = append(c.senders, w)
+	t.park(w)
+	t.mu.Unlock()
+	<-w.done
+	return w.err
+}
+
+func (c *Channel) receive() (any, error) {
+	t := c.tasks
+	t.mu.Lock()
+	if s := pop(&c.senders); s != nil {
+		v := s.value
+		t.fire(s, c, nil, nil)
+		t.mu.Unlock()
+		return v, nil
+	}
+	if c.closed {
+		t.mu.Unlock()
+		return nil, nil
+	}
+	w := newWaiter()
+	c.receivers = append(c.receivers, w)
+	t.park(w)
+	t.mu.Unlock()
+	<-w.done
+	return w.value, w.err
+}
+
+func (c *Channel) close() error {
+	t := c.tasks
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c.closed {
+		return fmt.Errorf("Channel is already closed.")
+	}
+	c.closed = true
+	for r := pop(&c.receivers); r != nil; r = pop(&c.receivers) {
+		t.fire(r, c, nil, nil)
+	}
+	for s := pop(&c.senders); s != nil; s = pop(&c.senders) {
+		t.fire(s, c, nil, fmt.Errorf("Send on closed channel."))
+	}
+	return nil
+}
+
+func (c Channel) String() string {
+	return "<channel>"
+}
+
+func NewChannel(interp *Interpreter) *Channel {
+	return &Channel{
+		tasks: interp.tasks,
+	}
+}
+
+var channelMethods = map[string]*NativeMethod{
+	"send": {
+		name:  "send",
+		arity: 1,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return nil, this.(*Channel).send(args[0])
+		},
+	},
+	"receive": {
+		name:  "receive",
+		arity: 0,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return this.(*Channel).receive()
+		},
+	},
+	"close": {
+		name:  "close",
+		arity: 0,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return nil, this.(*Channel).close()
+		},
+	},
+}
+
+func defineChannelGlobals(env *environment.Environment) {
+	env.Define("channel", NewNativeFunction("channel", 0, func(interp *Interpreter, args []any) (any, error) {
+		return NewChannel(interp), nil
+	}))
+	env.Define("send", NewNativeFunction("send", 2, func(interp *Interpreter, args []any) (any, error) {
+		ch, ok := args[0].(*Channel)
+		if !ok {
+			return nil, fmt.Errorf("First argument to 'send' must be a channel.")
+		}
+		return nil, ch.send(args[1])
+	}))
+	env.Define("receive", NewNativeFunction("receive", 1, func(interp *Interpreter, args []any) (any, error) {
+		ch, ok := args[0].(*Channel)
+		if !ok {
+			return nil, fmt.Errorf("Argument to 'receive' must be a channel.")
+		}
+		return ch.receive()
+	}))
+	env.Define("close", NewNativeFunction("close", 1, func(interp *Interpreter, args []any) (any, error) {
+		ch, ok := args[0].(*Channel)
+		if !ok {
+			return nil, fmt.Errorf("Argument to 'close' must be a channel.")
+		}
+		return nil, ch.close()
+	}))
+	env.Define("select", NewNativeFunction("select", variadicArity, nativeSelect))
+}
+
+// nativeSelect takes channel and handler pairs, waits until one of the
+// channels can be received from and calls its handler with the value.
+func nativeSelect(interp *Interpreter, args []any) (any, error) {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, fmt.Errorf("'select' expects channel and handler pairs.")
+	}
+	channels := make([]*Channel, len(args)/2)
+	handlers := make([]Callable, len(args)/2)
+	for idx := 0; idx < len(args); idx += 2 {
+		ch, ok := args[idx].(*Channel)
+		if !ok {
+			return nil, fmt.Errorf("'select' expects channel and handler pairs.")
+		}
+		handler, ok := args[idx+1].(Callable)
+		if !ok || handler.Arity() != 1 {
+			return nil, fmt.Errorf("'select' handlers must be functions of one argument.")
+		}
+		channels[idx/2] = ch
+		handlers[idx/2] = handler
+	}
+	chosen, value, err := selectReceive(interp.tasks, channels)
+	if err != nil {
+		return nil, err
+	}
+	return handlers[chosen].Call(interp, []any{value})
+}
+
+// selectReceive receives from the first of channels that is ready and
+// returns its index with the value.
+func selectReceive(t *taskGroup, channels []*Channel) (int, any, error) {
+	t.mu.Lock()
+	for idx, ch := range channels {
+		if s := pop(&ch.senders); s != nil {
+			v := s.value
+			t.fire(s, ch, nil, nil)
+			t.mu.Unlock()
+			return idx, v, nil
+		}
+		if ch.closed {
+			t.mu.Unlock()
+			return idx, nil, nil
+		}
+	}
+	w := newWaiter()
+	for _, ch := range channels {
+		ch.receivers = append(ch.receivers, w)
+	}
+	t.park(w)
+	t.mu.Unlock()
+	<-w.done
+	if w.err != nil {
+		return 0, nil, w.err
+	}
+	for idx, ch := range channels {
+		if ch == w.from {
+			return idx, w.value, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("'select' woken by an unknown channel.")
+}
@@ -0,0 +1,78 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/stmt"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+type Enum struct {
+	name     string
+	variants []*EnumVariant
+}
+
+func (e *Enum) Get(name *token.Token) (any, error) {
+	for _, v := range e.variants {
+		if v.name == name.Text {
+			return v, nil
+		}
+	}
+	return nil, NewRuntimeError(name, fmt.Sprintf("Undefined variant '%s' of enum %s.", name.Text, e.name))
+}
+
+// Iterator walks the variants in declaration order.
+func (e *Enum) Iterator() Iterator {
+	return &enumIterator{enum: e}
+}
+
+func (e Enum) String() string {
+	return fmt.Sprintf("<enum %s>", e.name)
+}
+
+func NewEnum(declaration *stmt.EnumStmt) *Enum {
+	enum := &Enum{
+		name: declaration.Name.Text,
+	}
+	for ordinal, v := range declaration.Variants {
+		enum.variants = append(enum.variants, &EnumVariant{
+			enum:    enum,
+			name:    v.Text,
+			ordinal: ordinal,
+		})
+	}
+	return enum
+}
+
+type EnumVariant struct {
+	enum    *Enum
+	name    string
+	ordinal int
+}
+
+func (v *EnumVariant) Get(name *token.Token) (any, error) {
+	switch name.Text {
+	case "name":
+		return v.name, nil
+	case "ordinal":
+		return float64(v.ordinal), nil
+	}
+	return nil, NewRuntimeError(name, fmt.Sprintf("Undefined property '%s'.", name.Text))
+}
+
+func (v EnumVariant) String() string {
+	return fmt.Sprintf("%s.%s", v.enum.name, v.name)
+}
+
+type enumIterator struct {
+	enum *Enum
+	idx  int
+}
+
+func (it *enumIterator) Next() (any, bool) {
+	if it.idx >= len(it.enum.variants) {
+		return nil, false
+	}
+	it.idx++
+	return it.enum.variants[it.idx-1], true
+}
@@ -3,6 +3,7 @@ package interpreter
 import (
 	"fmt"
 
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/errors"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
 )
 
@@ -21,3 +22,13 @@ func NewRuntimeError(op *token.Token, msg string) *RuntimeError {
 func (e RuntimeError) Error() string {
 	return fmt.Sprintf("%s\n[line %v]", e.msg, e.op.Line)
 }
+
+// wrapRuntimeError attaches the line of t to errors returned by native
+// callables, runtime errors that already carry a line are kept as is.
+func wrapRuntimeError(t *token.Token, err error) error {
+	switch err.(type) {
+	case *RuntimeError, *errors.RuntimeError:
+		return err
+	}
+	return NewRuntimeError(t, err.Error())
+}
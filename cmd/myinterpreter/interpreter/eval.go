@@ -0,0 +1,73 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// nativeEval evaluates an expression in the environment eval is called
+// from and returns its value.
+func nativeEval(interp *Interpreter, args []any) (any, error) {
+	tokens, err := lexSource("eval", args[0])
+	if err != nil {
+		return nil, err
+	}
+	exp, errs := parser.New(tokens).ParseExpression()
+	if errs != nil {
+		return nil, syntaxError("eval", errs)
+	}
+	value, _ := interp.Eval(exp)
+	return value, nil
+}
+
+// nativeExec runs statements in the environment exec is called from, so
+// declarations stay visible to the caller. The snippet is not part of the
+// calling function, a return or defer in it is outside of a function body.
+func nativeExec(interp *Interpreter, args []any) (any, error) {
+	tokens, err := lexSource("exec", args[0])
+	if err != nil {
+		return nil, err
+	}
+	program, errs := parser.New(tokens).ParseProgram()
+	if errs != nil {
+		return nil, syntaxError("exec", errs)
+	}
+	functionCalls, returnCalls, defers := interp.functionCalls, interp.returnCalls, interp.defers
+	interp.functionCalls, interp.returnCalls, interp.defers = 0, 0, nil
+	for _, s := range program {
+		interp.exec(s)
+		if interp.isErrorOcured() {
+			break
+		}
+	}
+	interp.functionCalls, interp.returnCalls, interp.defers = functionCalls, returnCalls, defers
+	interp.out = nil
+	return nil, nil
+}
+
+func lexSource(native string, v any) ([]*token.Token, error) {
+	source, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("Argument to '%s' must be a string.", native)
+	}
+	lex := lexer.New(source)
+	if errs := lex.Lex(); errs != nil {
+		return nil, syntaxError(native, errs)
+	}
+	return lex.Tokens(), nil
+}
+
+// syntaxError turns lex and parse errors into a runtime error, so a bad
+// source fails like any other call instead of stopping with exit code 65.
+func syntaxError(native string, errs []error) error {
+	return fmt.Errorf("Syntax error in '%s' source: %s", native, errs[0])
+}
+
+func defineEvalGlobals(env *environment.Environment) {
+	env.Define("eval", NewNativeFunction("eval", 1, nativeEval))
+	env.Define("exec", NewNativeFunction("exec", 1, nativeExec))
+}
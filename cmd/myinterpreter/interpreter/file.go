@@ -0,0 +1,186 @@
+package interpreter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+)
+
+// File is the handle returned by open(), its methods fail once it is
+// closed.
+type File struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	reader *bufio.Reader
+	closed bool
+}
+
+func (f *File) checkOpen() error {
+	if f.closed {
+		return fmt.Errorf("File '%s' is closed.", f.path)
+	}
+	return nil
+}
+
+func (f *File) readAll() (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(f.reader)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read file '%s'.", f.path)
+	}
+	return string(b), nil
+}
+
+// readLine returns the next line without its line ending, or nil at the
+// end of the file.
+func (f *File) readLine() (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+	line, err := f.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Cannot read file '%s'.", f.path)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// readBytes is readAll for binary files.
+func (f *File) readBytes() (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(f.reader)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read file '%s'.", f.path)
+	}
+	return NewBytes(b), nil
+}
+
+func (f *File) write(data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkOpen(); err != nil {
+		return err
+	}
+	if _, err := f.file.Write(data); err != nil {
+		return fmt.Errorf("Cannot write file '%s'.", f.path)
+	}
+	return nil
+}
+
+// close can be called again on a closed file, so closing a file by hand
+// inside a with block is fine.
+func (f *File) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("Cannot close file '%s'.", f.path)
+	}
+	return nil
+}
+
+func (f *File) String() string {
+	return fmt.Sprintf("<file %s>", f.path)
+}
+
+var fileModes = map[string]int{
+	"r": os.O_RDONLY,
+	"w": os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
+	"a": os.O_WRONLY | os.O_CREATE | os.O_APPEND,
+}
+
+func NewFile(path string, mode string) (*File, error) {
+	flag, ok := fileModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("File mode must be \"r\", \"w\" or \"a\" but got \"%s\".", mode)
+	}
+	file, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open file '%s'.", path)
+	}
+	return &File{
+		path:   path,
+		file:   file,
+		reader: bufio.NewReader(file),
+	}, nil
+}
+
+func fileMethod(name string, arity int, fn func(this *File, args []any) (any, error)) *NativeMethod {
+	return &NativeMethod{
+		name:  name,
+		arity: arity,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return fn(this.(*File), args)
+		},
+	}
+}
+
+var fileMethods = map[string]*NativeMethod{
+	"read": fileMethod("read", 0, func(this *File, args []any) (any, error) {
+		return this.readAll()
+	}),
+	"readLine": fileMethod("readLine", 0, func(this *File, args []any) (any, error) {
+		return this.readLine()
+	}),
+	"readBytes": fileMethod("readBytes", 0, func(this *File, args []any) (any, error) {
+		return this.readBytes()
+	}),
+	// write takes a string, written as utf8, or bytes.
+	"write": fileMethod("write", 1, func(this *File, args []any) (any, error) {
+		switch t := args[0].(type) {
+		case string:
+			return nil, this.write([]byte(t))
+		case *Bytes:
+			return nil, this.write(t.snapshot())
+		}
+		return nil, fmt.Errorf("Argument to 'write' must be a string or bytes.")
+	}),
+	"close": fileMethod("close", 0, func(this *File, args []any) (any, error) {
+		return nil, this.close()
+	}),
+}
+
+// nativeOpen is open(path) to read a file or open(path, mode) with mode
+// "r", "w" or "a".
+func nativeOpen(interp *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("Expected 1 to 2 arguments but got %v.", len(args))
+	}
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("Path passed to 'open' must be a string.")
+	}
+	mode := "r"
+	if len(args) == 2 {
+		mode, ok = args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("Mode passed to 'open' must be a string.")
+		}
+	}
+	return NewFile(path, mode)
+}
+
+func defineFileGlobals(env *environment.Environment) {
+	env.Define("open", NewNativeFunction("open", variadicArity, nativeOpen))
+}
@@ -0,0 +1,71 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expression"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/stmt"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// Fragment is a piece of code, the value of a quote and of every argument
+// of a procedural macro. It holds either an expression or statements.
+type Fragment struct {
+	Exp   expression.Expression
+	Stmts []stmt.Stmt
+	// Quoted is set for code written in a quote, the other fragments are
+	// code of the macro caller that is spliced in as is.
+	Quoted bool
+	// Values are the values of the unquotes of a quote, taken when the
+	// quote was evaluated.
+	Values map[*expression.UnquoteExpression]any
+}
+
+// Get gives the value of a literal fragment, so a macro can compute with
+// the numbers and strings it is called with.
+func (f *Fragment) Get(name *token.Token) (any, error) {
+	if name.Text != "value" {
+		return nil, NewRuntimeError(name, fmt.Sprintf("Undefined property '%s'.", name.Text))
+	}
+	literal, ok := f.Exp.(*expression.LiteralExpression)
+	if !ok || literal.Val.Type == token.BYTES {
+		return nil, NewRuntimeError(name, "Only a literal has a value.")
+	}
+	return literal.Val.TokenValue.GetValue(), nil
+}
+
+func (f *Fragment) String() string {
+	return "<code>"
+}
+
+func (i *Interpreter) VisitQuoteExpression(q *expression.QuoteExpression) {
+	values := map[*expression.UnquoteExpression]any{}
+	for _, u := range q.Unquotes {
+		value, _ := i.Eval(u.Exp)
+		if i.isErrorOcured() {
+			return
+		}
+		values[u] = value
+	}
+	fragment := &Fragment{Exp: q.Exp, Quoted: true, Values: values}
+	if q.Exp == nil {
+		fragment.Stmts = q.Block.(*stmt.BlockStmt).Statements
+	}
+	i.out = fragment
+}
+
+// VisitUnquoteExpression is never reached for a program the parser
+// accepted, a quote evaluates its unquotes without running its code.
+func (i *Interpreter) VisitUnquoteExpression(u *expression.UnquoteExpression) {
+	i.onError(NewRuntimeError(u.Keywoard, "Can't use 'unquote' outside of a quote."))
+}
+
+// RunMacro calls the body of a procedural macro with args and returns the
+// code it computed.
+func (i *Interpreter) RunMacro(m *stmt.MacroStmt, args []any) (any, []error) {
+	declaration := stmt.NewFunctionDeclarationStmt(m.Name, m.Body, m.Params, nil, nil, nil, nil, nil, m.Name, nil)
+	result, _ := NewFunction(declaration, i.globals).Call(i, args)
+	errs := append(i.errs, i.tasks.wait()...)
+	i.errs, i.out = nil, nil
+	return result, errs
+}
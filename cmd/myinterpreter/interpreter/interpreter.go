@@ -18,13 +18,46 @@ type Interpreter struct {
 	returnCalls   int
 	functionCalls int
 	errs          []error
+	tasks         *taskGroup
+	noChecks      bool
+	// defers holds one frame of deferred expressions per active function call.
+	defers [][]deferred
+	// loopControl is set by break and continue while the interpreter unwinds
+	// to the loop they target.
+	loopControl *loopControl
+	// nilChain is set when an optional link met nil, the guarded links after
+	// it in the same chain are skipped.
+	nilChain bool
+}
+
+type loopControl struct {
+	keywoard *token.Token
+	label    *token.Token
+}
+
+// targets reports whether the loop labeled label is the one this break or
+// continue refers to.
+func (c *loopControl) targets(label *token.Token) bool {
+	return c.label == nil || (label != nil && label.Text == c.label.Text)
+}
+
+type deferred struct {
+	exp expression.Expression
+	env *environment.Environment
 }
 
 type Callable interface {
-	Call(interp *Interpreter, args []any) any
+	Call(interp *Interpreter, args []any) (any, error)
 	Arity() int
 }
 
+// variadicArity marks a Callable that accepts any number of arguments.
+const variadicArity = -1
+
+type PropertyHolder interface {
+	Get(name *token.Token) (any, error)
+}
+
 func (i *Interpreter) Interp(program []stmt.Stmt) (any, []error) {
 	for _, s := range program {
 		if i.isErrorOcured() {
@@ -32,9 +65,15 @@ func (i *Interpreter) Interp(program []stmt.Stmt) (any, []error) {
 		}
 		i.exec(s)
 	}
+	i.errs = append(i.errs, i.tasks.wait()...)
 	return i.out, i.errs
 }
 
+// DisableChecks turns assert statements and function contracts into no-ops.
+func (i *Interpreter) DisableChecks() {
+	i.noChecks = true
+}
+
 func (i Interpreter) isErrorOcured() bool {
 	return i.errs != nil
 }
@@ -66,22 +105,183 @@ func (i *Interpreter) VisitWhileStmt(s *stmt.WhileStmt) {
 			break
 		}
 		i.exec(s.Body)
-		if i.isErrorOcured() || i.isReturnCallOccured() {
+		if i.loopShouldStop(s.Label) {
+			break
+		}
+		if s.Increment != nil {
+			i.Eval(s.Increment)
+		}
+	}
+}
+
+// VisitWithStmt runs the body in its own scope holding the resource and
+// closes the resource afterwards, also after a return, a break or a
+// runtime error.
+func (i *Interpreter) VisitWithStmt(s *stmt.WithStmt) {
+	resource, _ := i.Eval(s.Init)
+	if i.isErrorOcured() {
+		return
+	}
+	closer, ok := closeMethod(resource)
+	if !ok {
+		i.onError(NewRuntimeError(s.Keywoard, fmt.Sprintf("Resource %s of 'with' has no close method.", repr(resource))))
+		return
+	}
+	env := environment.New(i.env)
+	env.Define(s.Name.Text, resource)
+	i.executeBlock(s.Body, env)
+	result := i.out
+	_, err := closer.Call(i, nil)
+	if err != nil {
+		i.errs = append(i.errs, wrapRuntimeError(s.Keywoard, err))
+	}
+	i.out = result
+}
+
+// closeMethod returns the close method of v bound to v.
+func closeMethod(v any) (Callable, bool) {
+	method, ok := builtinMethods(v)["close"]
+	if !ok {
+		return nil, false
+	}
+	return NewBoundMethod(v, method), true
+}
+
+func (i *Interpreter) VisitDoWhileStmt(s *stmt.DoWhileStmt) {
+	for {
+		i.exec(s.Body)
+		if i.loopShouldStop(s.Label) {
+			break
+		}
+		v, _ := i.Eval(s.Condition)
+		if !isTrue(v) {
+			break
+		}
+	}
+}
+
+func (i *Interpreter) VisitLoopStmt(s *stmt.LoopStmt) {
+	for {
+		i.exec(s.Body)
+		if i.loopShouldStop(s.Label) {
 			break
 		}
 	}
 }
 
+func (i *Interpreter) VisitForInStmt(s *stmt.ForInStmt) {
+	iterable, _ := i.Eval(s.Iterable)
+	if i.isErrorOcured() {
+		return
+	}
+	it, ok := iterate(iterable)
+	if !ok {
+		i.onError(NewRuntimeError(s.Name, "Can only iterate over ranges, strings, bytes, enums, sets and tuples."))
+		return
+	}
+	for {
+		v, ok := it.Next()
+		if !ok {
+			break
+		}
+		// every iteration gets its own variable so closures capture the
+		// value they saw
+		env := environment.New(i.env)
+		env.Define(s.Name.Text, v)
+		i.executeBlock([]stmt.Stmt{s.Body}, env)
+		if i.loopShouldStop(s.Label) {
+			break
+		}
+	}
+}
+
+// loopShouldStop is called after each iteration of the loop labeled label,
+// it consumes a break or continue aimed at that loop.
+func (i *Interpreter) loopShouldStop(label *token.Token) bool {
+	if i.isErrorOcured() || i.isReturnCallOccured() {
+		return true
+	}
+	if !i.isLoopControlOccured() {
+		return false
+	}
+	// a labeled break or continue for an outer loop leaves this one
+	if !i.loopControl.targets(label) {
+		return true
+	}
+	isBreak := i.loopControl.keywoard.Type == token.BREAK
+	i.loopControl = nil
+	return isBreak
+}
+
+func (i *Interpreter) VisitBreakStmt(s *stmt.BreakStmt) {
+	i.loopControl = &loopControl{keywoard: s.Keywoard, label: s.Label}
+}
+
+func (i *Interpreter) VisitContinueStmt(s *stmt.ContinueStmt) {
+	i.loopControl = &loopControl{keywoard: s.Keywoard, label: s.Label}
+}
+
 func (i Interpreter) isReturnCallOccured() bool {
 	return i.returnCalls > 0
 }
 
+func (i Interpreter) isLoopControlOccured() bool {
+	return i.loopControl != nil
+}
+
 func (i Interpreter) isFunctionCallOccured() bool {
 	return i.functionCalls > 0
 }
 
 func (i *Interpreter) VisitFunctionDeclarationStmt(s *stmt.FunctionDeclarationStmt) {
-	i.env.Define(s.Name.Text, NewFunction(s, i.env))
+	if len(s.Decorators) == 0 {
+		i.env.Define(s.Name.Text, NewFunction(s, i.env))
+		return
+	}
+	decorators := make([]any, len(s.Decorators))
+	for idx, d := range s.Decorators {
+		decorators[idx], _ = i.Eval(d)
+	}
+	if i.isErrorOcured() {
+		return
+	}
+	// decorators are evaluated top down but applied bottom up, the one
+	// closest to the function wraps it first
+	var value any = NewFunction(s, i.env)
+	for idx := len(decorators) - 1; idx >= 0; idx-- {
+		decorator, ok := decorators[idx].(Callable)
+		if !ok {
+			i.onError(NewRuntimeError(s.Name, "Decorators must be functions."))
+			return
+		}
+		if decorator.Arity() != variadicArity && decorator.Arity() != 1 {
+			i.onError(NewRuntimeError(s.Name, fmt.Sprintf("Decorators must take 1 argument but got one taking %v.", decorator.Arity())))
+			return
+		}
+		decorated, err := decorator.Call(i, []any{value})
+		if err != nil {
+			i.onError(wrapRuntimeError(s.Name, err))
+			return
+		}
+		if i.isErrorOcured() {
+			return
+		}
+		value = decorated
+	}
+	i.out = nil
+	i.env.Define(s.Name.Text, value)
+}
+
+// VisitMacroStmt does nothing, macros only exist until the macro pass has
+// expanded them.
+func (i *Interpreter) VisitMacroStmt(s *stmt.MacroStmt) {}
+
+func (i *Interpreter) VisitMacroCallExpression(e *expression.MacroCallExpression) {
+	i.onError(NewRuntimeError(e.Name, fmt.Sprintf("Macro '%s' was not expanded.", e.Name.Text)))
+}
+
+func (i *Interpreter) VisitEnumStmt(s *stmt.EnumStmt) {
+	i.env.Define(s.Name.Text, NewEnum(s))
 }
 
 func (i *Interpreter) VisitReturnStmt(s *stmt.ReturnStmt) {
@@ -125,7 +325,7 @@ func (i *Interpreter) executeBlock(stmts []stmt.Stmt, env *environment.Environme
 
 	for _, s := range stmts {
 		i.exec(s)
-		if i.isErrorOcured() || i.isReturnCallOccured() {
+		if i.isErrorOcured() || i.isReturnCallOccured() || i.isLoopControlOccured() {
 			i.env = prevEnv
 			break
 		}
@@ -144,6 +344,32 @@ func (i *Interpreter) VisitVarStmt(s *stmt.VarStmt) {
 	i.env.Define(s.Name.Text, value)
 }
 
+func (i *Interpreter) VisitUnpackStmt(s *stmt.UnpackStmt) {
+	value, _ := i.Eval(s.Init)
+	if i.isErrorOcured() {
+		return
+	}
+	i.unpack(s.Names, value, i.env)
+}
+
+// unpack defines names in env, a single name is bound to value and
+// several names to the elements of a tuple of the same length.
+func (i *Interpreter) unpack(names []*token.Token, value any, env *environment.Environment) bool {
+	if len(names) == 1 {
+		env.Define(names[0].Text, value)
+		return true
+	}
+	tuple, ok := value.(*Tuple)
+	if !ok || len(tuple.elements) != len(names) {
+		i.onError(NewRuntimeError(names[0], fmt.Sprintf("Expected a tuple of %v values to unpack but got %s.", len(names), repr(value))))
+		return false
+	}
+	for idx, name := range names {
+		env.Define(name.Text, tuple.elements[idx])
+	}
+	return true
+}
+
 func (i *Interpreter) VisitVarExpression(s *expression.VarExpression) {
 	val, err := i.env.Get(s.Name)
 	if err != nil {
@@ -179,15 +405,25 @@ func (i *Interpreter) VisitLogicalExpression(s *expression.LogicalExpression) {
 			return
 		}
 	}
+	if s.Op.Type == token.QUESTION_QUESTION {
+		if left != nil {
+			i.out = left
+			return
+		}
+	}
 
 	i.Eval(s.Rhs)
 }
 
 func (i Interpreter) String() string {
-	if i.out == nil {
+	return stringify(i.out)
+}
+
+func stringify(v any) string {
+	if v == nil {
 		return "nil"
 	}
-	return fmt.Sprintf("%v", i.out)
+	return fmt.Sprintf("%v", v)
 }
 
 func (i *Interpreter) VisitBinary(b *expression.BinaryExpression) {
@@ -196,8 +432,14 @@ func (i *Interpreter) VisitBinary(b *expression.BinaryExpression) {
 
 	lNum, rNum, isNumeric := matchOperandsType[float64](lhs, rhs)
 	lStr, rStr, isString := matchOperandsType[string](lhs, rhs)
+	lSet, rSet, isSet := matchOperandsType[*Set](lhs, rhs)
+	lBytes, rBytes, isBytes := matchOperandsType[*Bytes](lhs, rhs)
 	switch b.Op.Type {
 	case token.MINUS:
+		if isSet {
+			i.out = difference(lSet, rSet)
+			return
+		}
 		if !isNumeric {
 			i.onError(errors.NewRuntimeError(b.Op, "Operands must be numbers."))
 		}
@@ -206,6 +448,10 @@ func (i *Interpreter) VisitBinary(b *expression.BinaryExpression) {
 			return
 		}
 	case token.PLUS:
+		if isBytes {
+			i.out = concatBytes(lBytes, rBytes)
+			return
+		}
 		if !isNumeric && !isString {
 			i.onError(errors.NewRuntimeError(b.Op, "Operands must be two numbers or two strings."))
 		}
@@ -247,34 +493,377 @@ func (i *Interpreter) VisitBinary(b *expression.BinaryExpression) {
 			i.onError(errors.NewRuntimeError(b.Op, "Operands must be numbers."))
 		}
 		i.out = lNum <= rNum
+	case token.PIPE:
+		if !isSet {
+			i.onError(errors.NewRuntimeError(b.Op, "Operands must be sets."))
+			return
+		}
+		i.out = union(lSet, rSet)
+	case token.AMPERSAND:
+		if !isSet {
+			i.onError(errors.NewRuntimeError(b.Op, "Operands must be sets."))
+			return
+		}
+		i.out = intersection(lSet, rSet)
+	case token.DOT_DOT, token.DOT_DOT_EQUAL:
+		if !isNumeric {
+			i.onError(errors.NewRuntimeError(b.Op, "Range bounds must be numbers."))
+			return
+		}
+		i.out = NewRange(lNum, rNum, 1, b.Op.Type == token.DOT_DOT_EQUAL)
+	case token.IN:
+		found, err := contains(rhs, lhs)
+		if err != nil {
+			i.onError(wrapRuntimeError(b.Op, err))
+			return
+		}
+		i.out = found
 	case token.EQUAL_EQUAL:
-		i.out = rhs == lhs
+		i.out = isEqual(lhs, rhs)
 	case token.BANG_EQUAL:
-		i.out = rhs != lhs
+		i.out = !isEqual(lhs, rhs)
 	}
 
 }
 
 func (i *Interpreter) VisitFunctionCallExpression(g *expression.FunctionCallExpression) {
+	function, args, ok := i.evalCall(g)
+	if !ok {
+		return
+	}
+	value, err := function.Call(i, args)
+	if err != nil {
+		i.onError(wrapRuntimeError(g.RightParan, err))
+		return
+	}
+	i.out = value
+	i.nilChain = false
+}
+
+// skipsChain reports whether a link of a call chain is skipped, because it
+// is optional and object is nil or it is guarded by an optional link that
+// was. The result is nil then.
+func (i *Interpreter) skipsChain(object any, optional bool, guarded bool) bool {
+	if i.isErrorOcured() {
+		return false
+	}
+	i.nilChain = (guarded && i.nilChain) || (optional && object == nil)
+	if i.nilChain {
+		i.out = nil
+	}
+	return i.nilChain
+}
+
+func (i *Interpreter) evalCall(g *expression.FunctionCallExpression) (Callable, []any, bool) {
 	calle, _ := i.Eval(g.Callee)
+	if i.skipsChain(calle, false, g.Guarded) {
+		return nil, nil, false
+	}
 	argsValues := make([]any, len(g.Args))
 	for idx, a := range g.Args {
-		argV, err := i.Eval(a)
-		if err != nil {
-
-		}
+		argV, _ := i.Eval(a)
 		argsValues[idx] = argV
 	}
+	if i.isErrorOcured() {
+		return nil, nil, false
+	}
+	function, ok := i.callable(g.RightParan, calle, argsValues)
+	return function, argsValues, ok
+}
+
+// callable checks that calle can be called with args, t is the token
+// errors are reported at.
+func (i *Interpreter) callable(t *token.Token, calle any, args []any) (Callable, bool) {
 	function, ok := calle.(Callable)
 	if !ok {
-		i.onError(NewRuntimeError(g.RightParan, "Can only call functions and classes."))
+		i.onError(NewRuntimeError(t, "Can only call functions and classes."))
+		return nil, false
+	}
+	if function.Arity() != variadicArity && function.Arity() != len(args) {
+		i.onError(NewRuntimeError(t, fmt.Sprintf("Expected %v arguments but got %v.", function.Arity(), len(args))))
+		return nil, false
+	}
+	return function, true
+}
+
+func (i *Interpreter) VisitPipelineExpression(p *expression.PipelineExpression) {
+	lhs, _ := i.Eval(p.Lhs)
+	var calle any
+	args := []any{lhs}
+	if call, ok := p.Rhs.(*expression.FunctionCallExpression); ok {
+		calle, _ = i.Eval(call.Callee)
+		for _, a := range call.Args {
+			argV, _ := i.Eval(a)
+			args = append(args, argV)
+		}
+	} else {
+		calle, _ = i.Eval(p.Rhs)
+	}
+	if i.isErrorOcured() {
+		return
+	}
+	function, ok := i.callable(p.Op, calle, args)
+	if !ok {
+		return
+	}
+	value, err := function.Call(i, args)
+	if err != nil {
+		i.onError(wrapRuntimeError(p.Op, err))
+		return
+	}
+	i.out = value
+}
+
+func (i *Interpreter) VisitDeferStmt(s *stmt.DeferStmt) {
+	if len(i.defers) == 0 {
+		i.onError(NewRuntimeError(s.Keywoard, "defer is not allowed outside of a function body"))
+		return
+	}
+	top := len(i.defers) - 1
+	i.defers[top] = append(i.defers[top], deferred{exp: s.Exp, env: i.env})
+}
+
+// runDeferred evaluates frame in LIFO order. Every deferred expression runs
+// even after a runtime error, its own errors are reported after the
+// original ones.
+func (i *Interpreter) runDeferred(frame []deferred) {
+	errs := i.errs
+	for idx := len(frame) - 1; idx >= 0; idx-- {
+		i.errs = nil
+		prevEnv := i.env
+		i.env = frame[idx].env
+		i.Eval(frame[idx].exp)
+		i.env = prevEnv
+		errs = append(errs, i.errs...)
+	}
+	i.errs = errs
+}
+
+func (i *Interpreter) VisitAssertStmt(s *stmt.AssertStmt) {
+	if i.noChecks {
+		return
+	}
+	cond, _ := i.Eval(s.Condition)
+	if i.isErrorOcured() || isTrue(cond) {
+		return
+	}
+	msg := fmt.Sprintf("Assertion failed: %s", s.Source)
+	if s.Message != nil {
+		m, _ := i.Eval(s.Message)
+		if i.isErrorOcured() {
+			return
+		}
+		msg = fmt.Sprintf("%s: %s", msg, stringify(m))
+	}
+	i.onError(NewRuntimeError(s.Keywoard, msg))
+}
+
+// checkContracts evaluates contracts in env and reports the first one that
+// does not hold.
+func (i *Interpreter) checkContracts(contracts []*stmt.Contract, env *environment.Environment, failure string) {
+	if i.noChecks {
+		return
+	}
+	prevEnv := i.env
+	i.env = env
+	for _, c := range contracts {
+		cond, _ := i.Eval(c.Condition)
+		if i.isErrorOcured() {
+			break
+		}
+		if !isTrue(cond) {
+			i.onError(NewRuntimeError(c.Keywoard, fmt.Sprintf("%s: %s", failure, c.Source)))
+			break
+		}
+	}
+	i.env = prevEnv
+}
+
+func (i *Interpreter) VisitSpawnStmt(s *stmt.SpawnStmt) {
+	function, args, ok := i.evalCall(s.Call)
+	if !ok {
+		return
+	}
+	i.tasks.spawn(i.fork(), s.Keywoard, function, args)
+}
+
+func (i *Interpreter) VisitGetExpression(g *expression.GetExpression) {
+	object, _ := i.Eval(g.Object)
+	if i.isErrorOcured() || i.skipsChain(object, g.Optional, g.Guarded) {
+		return
+	}
+	if methods := builtinMethods(object); methods != nil {
+		method, ok := methods[g.Name.Text]
+		if !ok {
+			i.onError(NewRuntimeError(g.Name, fmt.Sprintf("Undefined property '%s'.", g.Name.Text)))
+			return
+		}
+		i.out = NewBoundMethod(object, method)
+		return
+	}
+	holder, ok := object.(PropertyHolder)
+	if !ok {
+		i.onError(NewRuntimeError(g.Name, fmt.Sprintf("Value of type %s has no properties.", typeName(object))))
+		return
+	}
+	value, err := holder.Get(g.Name)
+	if err != nil {
+		i.onError(err)
+		return
+	}
+	i.out = value
+}
+
+func (i *Interpreter) VisitIndexExpression(e *expression.IndexExpression) {
+	object, _ := i.Eval(e.Object)
+	if i.skipsChain(object, e.Optional, e.Guarded) {
+		return
+	}
+	index, _ := i.Eval(e.Index)
+	if i.isErrorOcured() {
+		return
+	}
+	var value any
+	var err error
+	switch t := object.(type) {
+	case string:
+		value, err = indexString(t, index)
+	case *Tuple:
+		value, err = indexTuple(t, index)
+	case *Bytes:
+		value, err = indexBytes(t, index)
+	default:
+		i.onError(NewRuntimeError(e.Bracket, "Only strings, bytes and tuples can be indexed."))
+		return
+	}
+	if err != nil {
+		i.onError(wrapRuntimeError(e.Bracket, err))
+		return
+	}
+	i.out = value
+	i.nilChain = false
+}
+
+func (i *Interpreter) VisitTupleExpression(e *expression.TupleExpression) {
+	values := make([]any, len(e.Elements))
+	for idx, el := range e.Elements {
+		values[idx], _ = i.Eval(el)
+	}
+	if i.isErrorOcured() {
+		return
+	}
+	i.out = NewTuple(values)
+}
+
+func (i *Interpreter) VisitComprehensionExpression(e *expression.ComprehensionExpression) {
+	result := NewSet()
+	i.comprehend(e, 0, i.env, result)
+	if i.isErrorOcured() {
+		return
+	}
+	i.out = result
+}
+
+// comprehend runs the clauses of e from idx on, every iteration of a for
+// clause gets its own environment nested in env.
+func (i *Interpreter) comprehend(e *expression.ComprehensionExpression, idx int, env *environment.Environment, result *Set) {
+	if idx == len(e.Clauses) {
+		value := i.evalIn(e.Element, env)
+		if !i.isErrorOcured() {
+			result.add(value)
+		}
+		return
+	}
+	clause := e.Clauses[idx]
+	if clause.Keywoard.Type == token.IF {
+		if cond := i.evalIn(clause.Condition, env); isTrue(cond) && !i.isErrorOcured() {
+			i.comprehend(e, idx+1, env, result)
+		}
+		return
+	}
+	iterable := i.evalIn(clause.Iterable, env)
+	if i.isErrorOcured() {
+		return
+	}
+	it, ok := iterate(iterable)
+	if !ok {
+		i.onError(NewRuntimeError(clause.Keywoard, "Can only iterate over ranges, strings, bytes, enums, sets and tuples."))
+		return
+	}
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		inner := environment.New(env)
+		if !i.unpack(clause.Names, v, inner) {
+			return
+		}
+		i.comprehend(e, idx+1, inner, result)
+		if i.isErrorOcured() {
+			return
+		}
+	}
+}
+
+func (i *Interpreter) evalIn(exp expression.Expression, env *environment.Environment) any {
+	prev := i.env
+	i.env = env
+	value, _ := i.Eval(exp)
+	i.env = prev
+	return value
+}
+
+func (i *Interpreter) VisitSetExpression(e *expression.SetExpression) {
+	values := make([]any, len(e.Elements))
+	for idx, el := range e.Elements {
+		values[idx], _ = i.Eval(el)
+	}
+	if i.isErrorOcured() {
 		return
 	}
-	if function.Arity() != len(argsValues) {
-		i.onError(NewRuntimeError(g.RightParan, fmt.Sprintf("Expected %v arguments but got %v.", function.Arity(), len(argsValues))))
+	i.out = NewSet(values...)
+}
+
+func (i *Interpreter) VisitMatchExpression(m *expression.MatchExpression) {
+	value, _ := i.Eval(m.Value)
+	if i.isErrorOcured() {
 		return
 	}
-	i.out = function.Call(i, argsValues)
+	for _, arm := range m.Arms {
+		env := environment.New(i.env)
+		if !i.matchPattern(arm.Pattern, value, env) {
+			if i.isErrorOcured() {
+				return
+			}
+			continue
+		}
+		prevEnv := i.env
+		i.env = env
+		matched := true
+		if arm.Guard != nil {
+			guard, _ := i.Eval(arm.Guard)
+			matched = isTrue(guard)
+		}
+		if matched && !i.isErrorOcured() {
+			i.Eval(arm.Body)
+		}
+		i.env = prevEnv
+		if matched || i.isErrorOcured() {
+			return
+		}
+	}
+	i.onError(NewRuntimeError(m.Keywoard, fmt.Sprintf("Non-exhaustive match: no arm matches %s.", stringify(value))))
+}
+
+func (i *Interpreter) matchPattern(p expression.Pattern, value any, env *environment.Environment) bool {
+	switch pt := p.(type) {
+	case *expression.ValuePattern:
+		v, _ := i.Eval(pt.Exp)
+		return !i.isErrorOcured() && isEqual(v, value)
+	case *expression.BindingPattern:
+		env.Define(pt.Name.Text, value)
+		return true
+	case *expression.WildcardPattern:
+		return true
+	}
+	return false
 }
 
 func (i *Interpreter) VisitGrouping(g *expression.GroupingExpression) {
@@ -305,6 +894,11 @@ func (i *Interpreter) onError(e error) {
 }
 
 func (i *Interpreter) VisitLiteral(u *expression.LiteralExpression) {
+	// bytes are mutable, every evaluation of a literal makes a new buffer
+	if u.Val.Type == token.BYTES {
+		i.out = NewBytes([]byte(u.Val.TokenValue.GetValue().(string)))
+		return
+	}
 	i.out = u.Val.TokenValue.GetValue()
 }
 
@@ -314,14 +908,33 @@ func New() *Interpreter {
 	return &Interpreter{
 		env:     globalEnv,
 		globals: globalEnv,
+		tasks:   &taskGroup{},
+	}
+}
+
+// fork returns an interpreter with its own execution state that shares
+// globals and spawned tasks with i.
+func (i *Interpreter) fork() *Interpreter {
+	return &Interpreter{
+		env:      i.globals,
+		globals:  i.globals,
+		tasks:    i.tasks,
+		noChecks: i.noChecks,
 	}
 }
 
 func defineGlobals(env *environment.Environment) {
 	env.Define("clock", NewClockFc())
+	defineChannelGlobals(env)
+	defineRangeGlobals(env)
+	defineSetGlobals(env)
+	defineEvalGlobals(env)
+	defineReflectGlobals(env)
+	defineFileGlobals(env)
+	defineBytesGlobals(env)
 }
 
-func matchOperandsType[V int | float64 | string](lhs any, rhs any) (V, V, bool) {
+func matchOperandsType[V int | float64 | string | *Set | *Bytes](lhs any, rhs any) (V, V, bool) {
 	lhv, lOk := lhs.(V)
 	rhv, rOk := rhs.(V)
 	return lhv, rhv, lOk && rOk
@@ -341,8 +954,8 @@ func isTrue(v any) bool {
 type NativeClock struct {
 }
 
-func (c *NativeClock) Call(interp *Interpreter, args []any) any {
-	return float64(time.Now().Unix())
+func (c *NativeClock) Call(interp *Interpreter, args []any) (any, error) {
+	return float64(time.Now().Unix()), nil
 }
 
 func (c NativeClock) Arity() int {
@@ -362,20 +975,37 @@ type Function struct {
 	declaration *stmt.FunctionDeclarationStmt
 }
 
-func (c *Function) Call(interp *Interpreter, args []any) any {
+func (c *Function) Call(interp *Interpreter, args []any) (any, error) {
 	env := environment.New(c.closure)
 	interp.functionCalls += 1
 	startReturnCalls := interp.returnCalls
 	for i := 0; i < len(c.declaration.Args); i++ {
 		env.Define(c.declaration.Args[i].Text, args[i])
 	}
-	interp.executeBlock(c.declaration.Body, env)
+	interp.checkContracts(c.declaration.Requires, env, "Precondition failed")
+	interp.defers = append(interp.defers, nil)
+	if !interp.isErrorOcured() {
+		interp.executeBlock(c.declaration.Body, env)
+	}
+	frame := interp.defers[len(interp.defers)-1]
+	interp.defers = interp.defers[:len(interp.defers)-1]
 	interp.functionCalls -= 1
 	// decrement return calls only if return was called inside function
 	if interp.returnCalls > startReturnCalls {
 		interp.returnCalls -= 1
 	}
-	return interp.out
+	result := interp.out
+	if len(frame) > 0 {
+		interp.runDeferred(frame)
+		interp.out = result
+	}
+	if len(c.declaration.Ensures) > 0 && !interp.isErrorOcured() {
+		resultEnv := environment.New(env)
+		resultEnv.Define("result", result)
+		interp.checkContracts(c.declaration.Ensures, resultEnv, "Postcondition failed")
+		interp.out = result
+	}
+	return result, nil
 }
 
 func (c Function) Arity() int {
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
 )
 
 func TestInterpreter(t *testing.T) {
@@ -603,3 +605,1349 @@ func TestReturnControllFlowStmt(t *testing.T) {
 		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
 	}
 }
+
+func TestNilCoalescingStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var count = 0;
+		fun next() {
+			count = count + 1;
+			return count;
+		}
+		var a = nil;
+		print a ?? "default";
+		print false ?? "default";
+		print a ?? nil ?? 3;
+		print 1 ?? next();
+		print count;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "default\nfalse\n3\n1\n0\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestOptionalChaining(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		enum Color { Red, Green }
+		var count = 0;
+		fun next() {
+			count = count + 1;
+			return count;
+		}
+		var a = nil;
+		print nil?.name;
+		print nil?.upper();
+		print nil?[0];
+		print a?.upper().length();
+		print a?[next()];
+		print count;
+		print "abc"?.upper();
+		print "abc"?[1];
+		print "ab"?.upper().length();
+		print Color.Green?.name;
+		print a?.upper() ?? "default";
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "nil\nnil\nnil\nnil\nnil\n0\nABC\nb\n2\nGreen\ndefault\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+	}
+}
+
+func TestOptionalChainingErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `"abc"?.upper.length();`, expected: "Value of type function has no properties.\n[line 1]"},
+	})
+}
+
+func TestMatchExpression(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		fun describe(n) {
+			return match (n) {
+				0 => "zero",
+				-1 => "minus one",
+				"hi" => "greeting",
+				x if x > 100 => "big",
+				x if x > 0 => x * 2,
+				_ => "other",
+			};
+		}
+		var x = "outer";
+		print describe(0);
+		print describe(-1);
+		print describe("hi");
+		print describe(101);
+		print describe(4);
+		print describe(-5);
+		print x;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "zero\nminus one\ngreeting\nbig\n8\nother\nouter\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestNonExhaustiveMatchExpression(t *testing.T) {
+	lex := lexer.New(`
+		print match (3) {
+			1 => "one",
+			n if n > 5 => "big",
+		};
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	if errs == nil {
+		t.Errorf("TestNonExhaustiveMatchExpression expected runtime error, got: %v", errs)
+		return
+	}
+	expected := "Non-exhaustive match: no arm matches 3.\n[line 2]"
+	if errs[0].Error() != expected {
+		t.Errorf("TestNonExhaustiveMatchExpression Error, got: %s, want: %s", errs[0].Error(), expected)
+	}
+}
+
+func TestEnumStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		enum Color { Red, Green, Blue }
+		enum Light { Red }
+		var c = Color.Green;
+		print c;
+		print c.name;
+		print c.ordinal;
+		print c == Color.Green;
+		print c == Color.Blue;
+		print Light.Red == Color.Red;
+		print match (c) {
+			Color.Red => "stop",
+			Color.Green => "go",
+			_ => "wait",
+		};
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "Color.Green\nGreen\n1\ntrue\nfalse\nfalse\ngo\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestSpawnChannelsStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var counter = 0;
+		var acquire = channel();
+		var release = channel();
+		fun lock(n) {
+			for (var i = 0; i < n; i = i + 1) {
+				send(acquire, nil);
+				receive(release);
+			}
+		}
+		fun worker(id, results) {
+			var i = 0;
+			while (i < 50) {
+				receive(acquire);
+				counter = counter + 1;
+				send(release, nil);
+				i = i + 1;
+			}
+			send(results, id);
+		}
+		spawn lock(150);
+		var results = channel();
+		spawn worker(1, results);
+		spawn worker(2, results);
+		spawn worker(3, results);
+		print receive(results) + receive(results) + receive(results);
+		print counter;
+
+		var numbers = channel();
+		var words = channel();
+		fun onNumber(n) { return n * 2; }
+		fun onWord(w) { return "word " + w; }
+		spawn send(words, "hi");
+		print select(numbers, onNumber, words, onWord);
+		close(numbers);
+		print receive(numbers);
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "6\n150\nword hi\nnil\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+	}
+}
+
+func TestSpawnErrorStmt(t *testing.T) {
+	lex := lexer.New(`
+		var ch = channel();
+		close(ch);
+		spawn close(ch);
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	expected := "Channel is already closed.\n[line 4]"
+	if len(errs) != 1 || errs[0].Error() != expected {
+		t.Errorf("TestSpawnErrorStmt Error, got: %v, want: %s", errs, expected)
+	}
+}
+
+func TestSpawnDeadlockStmt(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		output string
+		err    string
+	}{
+		{
+			name: "main blocked",
+			source: `
+				var c = channel();
+				send(c, 1);
+				print "unreachable";
+			`,
+			err: "Deadlock: all tasks are blocked.\n[line 3]",
+		},
+		{
+			name: "task blocked",
+			source: `
+				var c = channel();
+				spawn receive(c);
+				print "main done";
+			`,
+			output: "main done\n",
+			err:    "Deadlock: all tasks are blocked.\n[line 3]",
+		},
+		{
+			name: "last task finishes",
+			source: `
+				var c = channel();
+				fun worker() { print "worker done"; }
+				spawn worker();
+				print receive(c);
+			`,
+			output: "worker done\n",
+			err:    "Deadlock: all tasks are blocked.\n[line 5]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock stdout
+			rescueStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			lex := lexer.New(tt.source)
+			lex.Lex()
+			program, errs := parser.New(lex.Tokens()).ParseProgram()
+			if errs != nil {
+				t.Errorf("TEST %s non nil parser error %v", tt.name, errs)
+				return
+			}
+			_, errs = New().Interp(program)
+			// demock stdout
+			w.Close()
+			out, _ := io.ReadAll(r)
+			os.Stdout = rescueStdout
+			if string(out) != tt.output {
+				t.Errorf("TEST %s got output: %q, want: %q", tt.name, out, tt.output)
+			}
+			if len(errs) != 1 || errs[0].Error() != tt.err {
+				t.Errorf("TEST %s got: %v, want: %s", tt.name, errs, tt.err)
+			}
+		})
+	}
+}
+
+func TestTypeAnnotationsIgnoredStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var x: number = 1;
+		var label: string? = nil;
+		fun add(a: number, b: number): number {
+			return a + b;
+		}
+		fun apply(f: fun(number, number): number, v: list<number>?): any {
+			return f(x, 2);
+		}
+		print apply(add, nil);
+		print label ?? "none";
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "3\nnone\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestAssertStmt(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "assert with message",
+			input:    "var b = 0;\nassert b != 0, \"b is \" + \"zero\";",
+			expected: "Assertion failed: b != 0: b is zero\n[line 2]",
+		},
+		{
+			name:     "assert without message",
+			input:    "fun f(x) { return x; }\nassert -f(1) > 0 and !false;",
+			expected: "Assertion failed: -f(1) > 0 and !false\n[line 2]",
+		},
+		{
+			name:     "assert optional chaining",
+			input:    "var a = nil;\nassert a?.upper() != nil or a?[0] == 1;",
+			expected: "Assertion failed: a?.upper() != nil or a?[0] == 1\n[line 2]",
+		},
+		{
+			name:     "precondition",
+			input:    "fun div(a, b)\n requires b != 0 {\n return a / b;\n}\ndiv(1, 0);",
+			expected: "Precondition failed: b != 0\n[line 2]",
+		},
+		{
+			name:     "postcondition",
+			input:    "fun inc(x) ensures result > x {\n return x - 1;\n}\ninc(1);",
+			expected: "Postcondition failed: result > x\n[line 1]",
+		},
+		{
+			name:     "passing checks",
+			input:    "fun div(a, b) requires b != 0 ensures result != nil { return a / b; }\nassert div(4, 2) == 2;",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := lexer.New(tt.input)
+			lex.Lex()
+			program, errs := parser.New(lex.Tokens()).ParseProgram()
+			if errs != nil {
+				t.Errorf("TEST %s non nil parser error %v", tt.name, errs)
+				return
+			}
+			_, errs = New().Interp(program)
+			if tt.expected == "" {
+				if errs != nil {
+					t.Errorf("TEST %s non nil error %v", tt.name, errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Error() != tt.expected {
+				t.Errorf("TEST %s got: %v, want: %s", tt.name, errs, tt.expected)
+			}
+			interpreter := New()
+			interpreter.DisableChecks()
+			_, errs = interpreter.Interp(program)
+			if errs != nil {
+				t.Errorf("TEST %s with checks disabled non nil error %v", tt.name, errs)
+			}
+		})
+	}
+}
+
+func TestDeferStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		fun log(msg) {
+			print msg;
+		}
+		fun work(n) {
+			defer log("first");
+			defer log("second");
+			print "working";
+			if (n > 1) return n * 10;
+			print "small";
+		}
+		print work(2);
+		print work(1);
+		fun fail() {
+			defer log("cleanup");
+			defer missing();
+			print -"x";
+		}
+		fail();
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "working\nsecond\nfirst\n20\nworking\nsmall\nsecond\nfirst\nnil\ncleanup\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+	if len(errs) != 2 ||
+		errs[0].Error() != "Operand must be a number.\n[line 17]" ||
+		errs[1].Error() != "Undefined variable 'missing'.\n[line 16]" {
+		t.Errorf("TestDeferStmt unexpected errors %v", errs)
+	}
+}
+
+func TestLabeledLoopsStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		outer: for (var i = 0; i < 3; i = i + 1) {
+			var j = 0;
+			inner: while (j < 3) {
+				j = j + 1;
+				if (j == 2) continue outer;
+				if (i == 2) break outer;
+				print i * 10 + j;
+			}
+		}
+		for (var n = 0; n < 5; n = n + 1) {
+			if (n == 1) continue;
+			if (n == 3) break;
+			print n;
+		}
+		fun find() {
+			search: while (true) {
+				while (true) {
+					return "found";
+				}
+			}
+		}
+		print find();
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "1\n11\n0\n2\nfound\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestDoWhileAndLoopStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var n = 10;
+		do {
+			print n;
+			n = n + 1;
+		} while (n < 3);
+		var tries = 0;
+		loop {
+			tries = tries + 1;
+			if (tries < 3) continue;
+			print tries;
+			break;
+		}
+		var i = 0;
+		outer: loop {
+			do {
+				i = i + 1;
+				if (i == 2) continue;
+				if (i == 4) break outer;
+				print i;
+			} while (true);
+		}
+		fun retry() {
+			var attempt = 0;
+			loop {
+				attempt = attempt + 1;
+				if (attempt == 2) return attempt;
+			}
+		}
+		print retry();
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "10\n3\n1\n3\n2\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestWithStmt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var path = "` + path + `";
+		with (f = open(path, "w")) {
+			f.write("one
+two");
+		}
+		fun firstLine() {
+			with (f = open(path)) {
+				return f.readLine();
+			}
+		}
+		print firstLine();
+		var returned;
+		fun keep() {
+			with (f = open(path)) {
+				returned = f;
+				return 1;
+			}
+		}
+		keep();
+		var broken;
+		for (x in 0..3) {
+			with (f = open(path)) {
+				broken = f;
+				f.readLine();
+				if (x == 1) break;
+				print f.readLine();
+			}
+		}
+		with (f = open(path, "a")) {
+			f.write("!");
+		}
+		with (ch = channel()) {
+			print ch;
+		}
+		var failed;
+		with (f = open(path)) {
+			failed = f;
+			print f.read();
+			f.nope;
+		}
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := "one\ntwo\n<channel>\none\ntwo!\n"
+	if res != expected {
+		t.Errorf("TestWithStmt Error, got: %s, want: %s", res, expected)
+	}
+	if len(errs) != 1 || errs[0].Error() != "Undefined property 'nope'.\n[line 40]" {
+		t.Errorf("TestWithStmt wrong errors %v", errs)
+	}
+	for _, name := range []string{"returned", "broken", "failed"} {
+		v, _ := interpreter.globals.Get(token.NewToken(token.IDENTIFIER, 0, name, nil))
+		if f, ok := v.(*File); !ok || !f.closed {
+			t.Errorf("TestWithStmt %s is not a closed file: %v", name, v)
+		}
+	}
+}
+
+func TestWithErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `with (x = 1) {}`, expected: "Resource 1 of 'with' has no close method.\n[line 1]"},
+		{input: `open("/nonexistent/file");`, expected: "Cannot open file '/nonexistent/file'.\n[line 1]"},
+		{input: `open("x", "rw");`, expected: "File mode must be \"r\", \"w\" or \"a\" but got \"rw\".\n[line 1]"},
+	})
+}
+
+func TestBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var b = b"\x00\x01hi\xff";
+		print b;
+		print b[0] + b[4];
+		print b[2..4].decode("utf8");
+		print b + b"!";
+		b.set(0, 65);
+		b.append(b"\"");
+		b.append(10);
+		print b;
+		print b.length();
+		print bytes("hi").decode("hex");
+		print bytes("aGk=", "base64").decode("utf8");
+		print bytes(2);
+		print bytes(0..3);
+		print 104 in b;
+		print b"hi" in b;
+		for (x in b"ab") print x;
+		fun make() { return b"x"; }
+		make().append(1);
+		print make();
+		with (f = open("` + path + `", "w")) {
+			f.write(b"\x00\xfe");
+			f.write("ok");
+		}
+		with (f = open("` + path + `")) {
+			print f.readBytes();
+		}
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := `b"\x00\x01hi\xff"
+255
+hi
+b"\x00\x01hi\xff!"
+b"A\x01hi\xff\"\x0a"
+7
+6869
+hi
+b"\x00\x00"
+b"\x00\x01\x02"
+true
+true
+97
+98
+b"x"
+b"\x00\xfeok"
+`
+	if res != expected || errs != nil {
+		t.Errorf("TestBytes Error, got: %s, want: %s, errors: %v", res, expected, errs)
+	}
+}
+
+func TestBytesErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `b"\xff".decode("utf8");`, expected: "Bytes are not valid utf8.\n[line 1]"},
+		{input: `b"a".decode();`, expected: "Expected 1 arguments but got 0.\n[line 1]"},
+		{input: `b"a".decode(1);`, expected: "Encoding must be a string.\n[line 1]"},
+		{input: `bytes("a", 1);`, expected: "Encoding must be a string.\n[line 1]"},
+		{input: `b"a".decode("utf16");`, expected: "Unknown encoding 'utf16', expected \"utf8\", \"hex\" or \"base64\".\n[line 1]"},
+		{input: `bytes("zz", "hex");`, expected: "Invalid hex string.\n[line 1]"},
+		{input: `b"a".set(1, 0);`, expected: "Bytes index 1 is out of bounds for length 1.\n[line 1]"},
+		{input: `b"a".append(256);`, expected: "Byte value must be an integer from 0 to 255.\n[line 1]"},
+		{input: `b"a"[1];`, expected: "Bytes index 1 is out of bounds for length 1.\n[line 1]"},
+		{input: `"a" in b"a";`, expected: "Left operand of 'in' must be a number or bytes when searching bytes.\n[line 1]"},
+	})
+}
+
+func TestBuiltinMethods(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var s = "  Hello, World ";
+		print s.trim().upper();
+		print s.length();
+		print "abc".contains("b");
+		print "abcdef".substring(1, 3);
+		print "ab".repeat(3);
+		print "hello".indexOf("l");
+		print "a-b-c".replace("-", "+");
+		print (-2.5).abs();
+		print 2.5.floor();
+		print 16.sqrt();
+		var lower = "QUIET".lower;
+		print lower();
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "HELLO, WORLD\n15\ntrue\nbc\nababab\n2\na+b+c\n2.5\n2\n4\nquiet\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestBuiltinMethodErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `"abc".missing();`, expected: "Undefined property 'missing'.\n[line 1]"},
+		{input: `"abc".contains(1);`, expected: "Argument to 'contains' must be a string.\n[line 1]"},
+		{input: `"abc".substring(2, 5);`, expected: "Substring range [2, 5) is out of bounds for length 3.\n[line 1]"},
+		{input: `"abc".upper(1);`, expected: "Expected 0 arguments but got 1.\n[line 1]"},
+		{input: `true.x;`, expected: "Value of type bool has no properties.\n[line 1]"},
+	})
+}
+
+func TestRangeStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		for (i in 1..3) print i;
+		for (var i in 1..=2) print i;
+		for (i in range(6, 0, -3)) print i;
+		print (0..10).step(5);
+		print 4 in 0..10;
+		print 3 in (0..10).step(2);
+		print "ell" in "hello";
+		enum Color { Red, Green }
+		for (c in Color) print c;
+		var s = "hello";
+		print s[1] + s[1..3] + s[3..=4];
+		var fs = "";
+		for (ch in "ab") {
+			fun f() { return ch; }
+			fs = fs + f();
+		}
+		print fs;
+		outer: for (i in 0..3) {
+			for (j in 0..3) {
+				if (j == 1) continue outer;
+				if (i == 2) break outer;
+				print i + j;
+			}
+		}
+		for (x in range(0, 0.5, 0.1)) print x;
+		print 0.3 in range(0, 1, 0.1);
+		print 0.35 in range(0, 1, 0.1);
+		var count = 0;
+		for (x in range(10000000000000000, 10000000000000004, 1)) count = count + 1;
+		print count;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "1\n2\n1\n2\n6\n3\n(0..10).step(5)\ntrue\nfalse\ntrue\nColor.Red\nColor.Green\neello\nab\n0\n1\n0\n0.1\n0.2\n0.3\n0.4\ntrue\nfalse\n3\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `"a".."b";`, expected: "Range bounds must be numbers.\n[line 1]"},
+		{input: `range(0, 1, 0);`, expected: "Range step cannot be zero.\n[line 1]"},
+		{input: `1 in 2;`, expected: "Right operand of 'in' must be a range, string, bytes, enum, set or tuple.\n[line 1]"},
+		{input: `for (x in 1) print x;`, expected: "Can only iterate over ranges, strings, bytes, enums, sets and tuples.\n[line 1]"},
+		{input: `"abc"[3];`, expected: "String index 3 is out of bounds for length 3.\n[line 1]"},
+		{input: `"abc"[1..5];`, expected: "String slice 1..5 is out of bounds for length 3.\n[line 1]"},
+		{input: `true[0];`, expected: "Only strings, bytes and tuples can be indexed.\n[line 1]"},
+	})
+}
+
+func TestSetStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var ids = #{3, 1, 3, 2,};
+		print ids;
+		print 2 in ids;
+		print ids | #{5, 1};
+		print ids & #{2, 3, 9};
+		print ids - #{1};
+		ids.add("x");
+		print ids.remove(3);
+		print ids.size();
+		for (v in ids) print v;
+		print set("hello");
+		print set(0..3) == set(0..3);
+		print #{};
+		var s = #{1};
+		s.add(s);
+		print s;
+		var u = #{};
+		u.add((u,));
+		print u;
+		print (s, s);
+		print #{1, 2} == #{2, 1};
+		print #{1, 2} == #{1, 3};
+		print #{#{1}, (2, "a")} == #{(2, "a"), #{1}};
+		print #{1} != #{1, 2};
+		var t = #{1};
+		t.add(t);
+		print s == t;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "#{3, 1, 2}\ntrue\n#{3, 1, 2, 5}\n#{3, 2}\n#{3, 2}\ntrue\n3\n1\n2\nx\n#{\"h\", \"e\", \"l\", \"o\"}\ntrue\n#{}\n#{1, #{...}}\n#{(#{...},)}\n(#{1, #{...}}, #{1, #{...}})\ntrue\nfalse\ntrue\ntrue\ntrue\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `#{1} | 1;`, expected: "Operands must be sets.\n[line 1]"},
+		{input: `set(1);`, expected: "Argument to 'set' must be a range, string, bytes, enum, set or tuple.\n[line 1]"},
+	})
+}
+
+func TestTupleStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		fun divmod(a, b) {
+			var q = (a / b).floor();
+			return q, a - q * b;
+		}
+		var q, rest = divmod(17, 5);
+		print q;
+		print rest;
+		var t = divmod(9, 2);
+		print t;
+		print t[1];
+		print (1, "a") == (1, "a");
+		print (1, (2, 3)) != (1, (2, 4));
+		print (1,);
+		print #{(1, 2), (1, 2), (2, 1)};
+		print 2 in (1, 2, 3);
+		print (1, 2, 3)[1..3];
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "3\n2\n(4, 1)\n1\ntrue\ntrue\n(1,)\n#{(1, 2), (2, 1)}\ntrue\n(2, 3)\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestTupleErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `var a, b = (1, 2, 3);`, expected: "Expected a tuple of 2 values to unpack but got (1, 2, 3).\n[line 1]"},
+		{input: `var a, b = "ab";`, expected: "Expected a tuple of 2 values to unpack but got \"ab\".\n[line 1]"},
+		{input: `(1, 2)[2];`, expected: "Tuple index 2 is out of bounds for length 2.\n[line 1]"},
+	})
+}
+
+func TestDecoratorStmt(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		fun logged(f) {
+			fun wrapper(x) {
+				print "calling";
+				return f(x);
+			}
+			return wrapper;
+		}
+		fun times(n) {
+			print "times";
+			fun decorator(f) {
+				fun wrapper(x) { return f(x) * n; }
+				return wrapper;
+			}
+			return decorator;
+		}
+		@logged
+		@times(10)
+		fun inc(x) { return x + 1; }
+		print inc(1);
+		@times(2) fun fact(n) {
+			if (n <= 1) return 1;
+			return n * fact(n - 1);
+		}
+		print fact(3);
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "times\ncalling\n20\ntimes\n48\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestDecoratorErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `@nil fun f() {}`, expected: "Decorators must be functions.\n[line 1]"},
+		{input: `fun two(a, b) {} @two fun f() {}`, expected: "Decorators must take 1 argument but got one taking 2.\n[line 1]"},
+	})
+}
+
+func TestComprehension(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		print #{x * 2 for x in 0..6 if x > 2};
+		print #{(x, y) for x in 1..=3 for y in 1..=3 if x < y};
+		var pairs = #{("a", 1), ("b", 2)};
+		print #{k for k, v in pairs if v > 1};
+		var x = "outer";
+		print #{x for x in "aab"};
+		print x;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "#{6, 8, 10}\n#{(1, 2), (1, 3), (2, 3)}\n#{\"b\"}\n#{\"a\", \"b\"}\nouter\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+}
+
+func TestComprehensionErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `#{x for x in 1};`, expected: "Can only iterate over ranges, strings, bytes, enums, sets and tuples.\n[line 1]"},
+		{input: `#{a for a, b in 0..2};`, expected: "Expected a tuple of 2 values to unpack but got 0.\n[line 1]"},
+	})
+}
+
+func TestPipelineExpression(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		fun parse(s) { return s.trim(); }
+		fun keep(s, pred) {
+			if (pred(s)) return s;
+			return "";
+		}
+		fun nonEmpty(s) { return s.length() > 0; }
+		fun format(s) { return "<" + s + ">"; }
+		print "  hi " |> parse |> keep(nonEmpty) |> format;
+		print "   " |> parse |> keep(nonEmpty) |> format;
+		print 16 |> clock ?? 1;
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "<hi>\n<>\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+	if len(errs) != 1 || errs[0].Error() != "Expected 0 arguments but got 1.\n[line 11]" {
+		t.Errorf("TestPipelineExpression wrong errors %v", errs)
+	}
+}
+
+func TestEvalExec(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		var x = 2;
+		print eval("x * 21");
+		fun f(y) { return eval("y + x"); }
+		print f(1);
+		exec("var z = x + 1; print z;");
+		print z;
+		exec("fun g() { return 7; }");
+		print g();
+		fun h() {
+			exec("fun inner() { return 5; } print inner();");
+			print "after exec";
+			return 1;
+		}
+		print h();
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	fmt.Println(errs, parser.NewAstPrinter().PrintProgram(program))
+	expected := "42\n3\n3\n3\n7\n5\nafter exec\n1\n"
+	if res != expected {
+		t.Errorf("TestParser Error, got: %s, want: %s", res, expected)
+	}
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+	}
+}
+
+func TestEvalExecErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `eval("1 +");`, expected: "Syntax error in 'eval' source: 1 at end Expect expression.\n[line 1]"},
+		{input: `eval("1 2");`, expected: "Syntax error in 'eval' source: 1 at '2'Expect end of expression.\n[line 1]"},
+		{input: `exec("var a = $;");`, expected: "Syntax error in 'exec' source: [line 1] Error: Unexpected character: $\n[line 1]"},
+		{input: `exec(1);`, expected: "Argument to 'exec' must be a string.\n[line 1]"},
+		{input: `fun g() { exec("return 5;"); print "after exec"; return 1; } g();`, expected: "return is not allowed outside of a function body\n[line 1]"},
+	})
+}
+
+func TestReflection(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		enum Color { Red, Green }
+		fun add(a,  b) {
+			// sums its arguments
+			return a + b;
+		}
+		print type(1) + " " + type(add) + " " + type(Color) + " " + type(Color.Red) + " " + type((1,));
+		print arity(add);
+		print arity(range);
+		print name(add) + " " + name("a".upper);
+		print source(add);
+		print source(clock);
+		print fields(Color);
+		print fields(Color.Green);
+		print methods(1..2);
+		print hasField(Color, "Red");
+		print getField(Color.Green, "ordinal");
+	`)
+	lex.Lex()
+	tokens := lex.Tokens()
+	p := parser.New(tokens)
+	program, errs := p.ParseProgram()
+	if errs != nil {
+		t.Errorf("TestInterpreter non nil error %s", errs)
+		return
+	}
+	interpreter := New()
+	_, errs = interpreter.Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := "number function enum Color tuple\n2\nnil\nadd upper\nfun add(a,  b) {\n\t\t\t// sums its arguments\n\t\t\treturn a + b;\n\t\t}\nnil\n" +
+		"(\"Red\", \"Green\")\n(\"name\", \"ordinal\")\n(\"step\",)\ntrue\n1\n"
+	if res != expected || errs != nil {
+		t.Errorf("TestReflection Error, got: %s, want: %s, errors: %v", res, expected, errs)
+	}
+}
+
+func TestReflectionErrors(t *testing.T) {
+	checkRuntimeErrors(t, []runtimeErrorTest{
+		{input: `arity(1);`, expected: "Argument to 'arity' must be a function.\n[line 1]"},
+		{input: `fields("abc");`, expected: "Argument to 'fields' must be an enum or an enum variant.\n[line 1]"},
+		{input: `enum E { A } getField(E, "B");`, expected: "Undefined property 'B'.\n[line 1]"},
+		{input: `enum E { A } setField(E.A, "name", "B");`, expected: "Cannot set field 'name' of E.A, enums are immutable.\n[line 1]"},
+	})
+}
+
+type runtimeErrorTest struct {
+	input    string
+	expected string
+}
+
+// checkRuntimeErrors runs every input as a program and expects it to fail
+// with exactly the expected runtime error.
+func checkRuntimeErrors(t *testing.T, tests []runtimeErrorTest) {
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		lex.Lex()
+		program, errs := parser.New(lex.Tokens()).ParseProgram()
+		if errs != nil {
+			t.Errorf("%s non nil parser error %v", t.Name(), errs)
+			continue
+		}
+		_, errs = New().Interp(program)
+		if len(errs) != 1 || errs[0].Error() != tt.expected {
+			t.Errorf("%s %s got: %v, want: %s", t.Name(), tt.input, errs, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package interpreter
+
+type NativeFunction struct {
+	name  string
+	arity int
+	fn    func(interp *Interpreter, args []any) (any, error)
+}
+
+func (n *NativeFunction) Call(interp *Interpreter, args []any) (any, error) {
+	return n.fn(interp, args)
+}
+
+func (n NativeFunction) Arity() int {
+	return n.arity
+}
+
+func (n NativeFunction) String() string {
+	return "<native fn>"
+}
+
+func NewNativeFunction(name string, arity int, fn func(interp *Interpreter, args []any) (any, error)) *NativeFunction {
+	return &NativeFunction{
+		name:  name,
+		arity: arity,
+		fn:    fn,
+	}
+}
@@ -0,0 +1,281 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+)
+
+// Iterable is a value a for-in loop can walk over.
+type Iterable interface {
+	Iterator() Iterator
+}
+
+type Iterator interface {
+	Next() (any, bool)
+}
+
+// Range is the value of `a..b` and `a..=b`. It is lazy, elements are only
+// computed while iterating.
+type Range struct {
+	start     float64
+	stop      float64
+	step      float64
+	inclusive bool
+	// scale is 10 to the number of decimals of start and step, elements
+	// are rounded to it so 0.1 steps don't drift. It is 0 when they have
+	// too many decimals to round.
+	scale float64
+}
+
+func (r *Range) Iterator() Iterator {
+	return &rangeIterator{r: r}
+}
+
+// at returns the element k steps from start. Elements are computed from k
+// rather than by adding the step again and again, so errors don't add up.
+func (r *Range) at(k float64) float64 {
+	v := r.start + k*r.step
+	if r.scale != 0 {
+		v = math.Round(v*r.scale) / r.scale
+	}
+	return v
+}
+
+// inBounds reports whether v lies between start and stop, ignoring the step.
+func (r *Range) inBounds(v float64) bool {
+	if r.step > 0 {
+		return v >= r.start && (v < r.stop || r.inclusive && v == r.stop)
+	}
+	return v <= r.start && (v > r.stop || r.inclusive && v == r.stop)
+}
+
+func (r *Range) contains(v any) bool {
+	n, ok := v.(float64)
+	if !ok || !r.inBounds(n) {
+		return false
+	}
+	return r.at(math.Round((n-r.start)/r.step)) == n
+}
+
+func (r Range) String() string {
+	op := ".."
+	if r.inclusive {
+		op = "..="
+	}
+	if r.step == 1 {
+		return fmt.Sprintf("%v%s%v", r.start, op, r.stop)
+	}
+	return fmt.Sprintf("(%v%s%v).step(%v)", r.start, op, r.stop, r.step)
+}
+
+func NewRange(start float64, stop float64, step float64, inclusive bool) *Range {
+	r := &Range{
+		start:     start,
+		stop:      stop,
+		step:      step,
+		inclusive: inclusive,
+	}
+	if d := max(decimals(start), decimals(step)); d <= maxRangeDecimals {
+		r.scale = math.Pow(10, float64(d))
+	}
+	return r
+}
+
+// maxRangeDecimals is the most decimals a float64 keeps exactly enough for
+// rounding range elements to them.
+const maxRangeDecimals = 15
+
+// decimals returns the number of decimals of the shortest text of f.
+func decimals(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		return len(s) - idx - 1
+	}
+	return 0
+}
+
+// rangeIterator counts the steps taken in k, which also bounds ranges
+// whose elements are too large for the step to change them.
+type rangeIterator struct {
+	r *Range
+	k float64
+}
+
+func (it *rangeIterator) Next() (any, bool) {
+	v := it.r.at(it.k)
+	if !it.r.inBounds(v) {
+		return nil, false
+	}
+	it.k++
+	return v, true
+}
+
+// stringIterator walks a string one character at a time.
+type stringIterator struct {
+	runes []rune
+	idx   int
+}
+
+func (it *stringIterator) Next() (any, bool) {
+	if it.idx >= len(it.runes) {
+		return nil, false
+	}
+	it.idx++
+	return string(it.runes[it.idx-1]), true
+}
+
+func iterate(v any) (Iterator, bool) {
+	switch t := v.(type) {
+	case string:
+		return &stringIterator{runes: []rune(t)}, true
+	case Iterable:
+		return t.Iterator(), true
+	}
+	return nil, false
+}
+
+// contains implements `value in container`.
+func contains(container any, value any) (bool, error) {
+	switch t := container.(type) {
+	case string:
+		sub, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("Left operand of 'in' must be a string when searching a string.")
+		}
+		return strings.Contains(t, sub), nil
+	case *Range:
+		return t.contains(value), nil
+	case *Enum:
+		variant, ok := value.(*EnumVariant)
+		return ok && variant.enum == t, nil
+	case *Set:
+		return t.has(value), nil
+	case *Bytes:
+		return containsBytes(t, value)
+	case *Tuple:
+		for _, e := range t.elements {
+			if isEqual(e, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("Right operand of 'in' must be a range, string, bytes, enum, set or tuple.")
+}
+
+// indexPositions resolves a number or range index into positions of a
+// sequence of the given length, single is set for a number index. kind
+// names the sequence in errors.
+func indexPositions(kind string, index any, length int) (positions []int, single bool, err error) {
+	switch t := index.(type) {
+	case float64:
+		idx, err := indexArg("[]", t)
+		if err != nil || idx >= length {
+			return nil, false, fmt.Errorf("%s index %v is out of bounds for length %v.", kind, t, length)
+		}
+		return []int{idx}, true, nil
+	case *Range:
+		it := t.Iterator()
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			idx, err := indexArg("[]", v)
+			if err != nil || idx >= length {
+				return nil, false, fmt.Errorf("%s slice %v is out of bounds for length %v.", kind, t, length)
+			}
+			positions = append(positions, idx)
+		}
+		return positions, false, nil
+	}
+	return nil, false, fmt.Errorf("%s index must be a number or a range.", kind)
+}
+
+// indexString returns the character or the substring of s picked out by index.
+func indexString(s string, index any) (any, error) {
+	runes := []rune(s)
+	positions, _, err := indexPositions("String", index, len(runes))
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	for _, idx := range positions {
+		b.WriteRune(runes[idx])
+	}
+	return b.String(), nil
+}
+
+// indexTuple returns the element or the tuple of elements of t picked out
+// by index.
+func indexTuple(t *Tuple, index any) (any, error) {
+	positions, single, err := indexPositions("Tuple", index, len(t.elements))
+	if err != nil {
+		return nil, err
+	}
+	if single {
+		return t.elements[positions[0]], nil
+	}
+	elements := make([]any, len(positions))
+	for i, idx := range positions {
+		elements[i] = t.elements[idx]
+	}
+	return NewTuple(elements), nil
+}
+
+func rangeStep(v any) (float64, error) {
+	step, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("Range step must be a number.")
+	}
+	if step == 0 {
+		return 0, fmt.Errorf("Range step cannot be zero.")
+	}
+	return step, nil
+}
+
+var rangeMethods = map[string]*NativeMethod{
+	"step": {
+		name:  "step",
+		arity: 1,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			r := this.(*Range)
+			step, err := rangeStep(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewRange(r.start, r.stop, step, r.inclusive), nil
+		},
+	},
+}
+
+// nativeRange is range(stop), range(start, stop) or range(start, stop, step),
+// stop is exclusive.
+func nativeRange(interp *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("Expected 1 to 3 arguments but got %v.", len(args))
+	}
+	bounds := make([]float64, len(args))
+	for idx, arg := range args {
+		n, ok := arg.(float64)
+		if !ok {
+			return nil, fmt.Errorf("Arguments to 'range' must be numbers.")
+		}
+		bounds[idx] = n
+	}
+	switch len(bounds) {
+	case 1:
+		return NewRange(0, bounds[0], 1, false), nil
+	case 2:
+		return NewRange(bounds[0], bounds[1], 1, false), nil
+	}
+	step, err := rangeStep(args[2])
+	if err != nil {
+		return nil, err
+	}
+	return NewRange(bounds[0], bounds[1], step, false), nil
+}
+
+func defineRangeGlobals(env *environment.Environment) {
+	env.Define("range", NewNativeFunction("range", variadicArity, nativeRange))
+}
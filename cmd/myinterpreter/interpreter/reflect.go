@@ -0,0 +1,224 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// FieldHolder is a PropertyHolder that can list its properties, which is
+// what the reflection natives read and write.
+type FieldHolder interface {
+	PropertyHolder
+	Fields() []string
+}
+
+func (e *Enum) Fields() []string {
+	fields := make([]string, len(e.variants))
+	for i, v := range e.variants {
+		fields[i] = v.name
+	}
+	return fields
+}
+
+func (v *EnumVariant) Fields() []string {
+	return []string{"name", "ordinal"}
+}
+
+// typeName is the result of type(v), enum variants are named after their
+// enum.
+func typeName(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case Callable:
+		return "function"
+	case *Enum:
+		return "enum"
+	case *EnumVariant:
+		return t.enum.name
+	case *Range:
+		return "range"
+	case *Set:
+		return "set"
+	case *Tuple:
+		return "tuple"
+	case *Channel:
+		return "channel"
+	case *File:
+		return "file"
+	case *Bytes:
+		return "bytes"
+	case *Fragment:
+		return "code"
+	}
+	return "unknown"
+}
+
+// functionName is the name a function was declared with, decorated
+// functions have the name of whatever the decorator returned.
+func functionName(v any) (string, bool) {
+	switch t := v.(type) {
+	case *Function:
+		return t.declaration.Name.Text, true
+	case *NativeFunction:
+		return t.name, true
+	case *BoundMethod:
+		return t.method.name, true
+	case *NativeClock:
+		return "clock", true
+	}
+	return "", false
+}
+
+func fieldHolder(name string, v any) (FieldHolder, error) {
+	holder, ok := v.(FieldHolder)
+	if !ok {
+		return nil, fmt.Errorf("Argument to '%s' must be an enum or an enum variant.", name)
+	}
+	return holder, nil
+}
+
+func fieldName(name string, v any) (string, error) {
+	field, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("Field name passed to '%s' must be a string.", name)
+	}
+	return field, nil
+}
+
+func hasField(holder FieldHolder, field string) bool {
+	for _, f := range holder.Fields() {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func stringTuple(values []string) *Tuple {
+	elements := make([]any, len(values))
+	for i, v := range values {
+		elements[i] = v
+	}
+	return NewTuple(elements)
+}
+
+func nativeType(interp *Interpreter, args []any) (any, error) {
+	return typeName(args[0]), nil
+}
+
+// nativeArity is nil for functions taking any number of arguments.
+func nativeArity(interp *Interpreter, args []any) (any, error) {
+	fn, ok := args[0].(Callable)
+	if !ok {
+		return nil, fmt.Errorf("Argument to 'arity' must be a function.")
+	}
+	if fn.Arity() == variadicArity {
+		return nil, nil
+	}
+	return float64(fn.Arity()), nil
+}
+
+func nativeName(interp *Interpreter, args []any) (any, error) {
+	name, ok := functionName(args[0])
+	if !ok {
+		return nil, fmt.Errorf("Argument to 'name' must be a function.")
+	}
+	return name, nil
+}
+
+// nativeSource is nil for native functions, they have no source.
+func nativeSource(interp *Interpreter, args []any) (any, error) {
+	if _, ok := args[0].(Callable); !ok {
+		return nil, fmt.Errorf("Argument to 'source' must be a function.")
+	}
+	fn, ok := args[0].(*Function)
+	if !ok {
+		return nil, nil
+	}
+	return token.SourceBetween(fn.declaration.Keywoard, fn.declaration.RightBrace), nil
+}
+
+func nativeFields(interp *Interpreter, args []any) (any, error) {
+	holder, err := fieldHolder("fields", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return stringTuple(holder.Fields()), nil
+}
+
+// nativeMethods lists the built-in methods of a value in sorted order,
+// values without methods have none.
+func nativeMethods(interp *Interpreter, args []any) (any, error) {
+	names := []string{}
+	for name := range builtinMethods(args[0]) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return stringTuple(names), nil
+}
+
+func nativeHasField(interp *Interpreter, args []any) (any, error) {
+	holder, err := fieldHolder("hasField", args[0])
+	if err != nil {
+		return nil, err
+	}
+	field, err := fieldName("hasField", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return hasField(holder, field), nil
+}
+
+func nativeGetField(interp *Interpreter, args []any) (any, error) {
+	holder, err := fieldHolder("getField", args[0])
+	if err != nil {
+		return nil, err
+	}
+	field, err := fieldName("getField", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !hasField(holder, field) {
+		return nil, fmt.Errorf("Undefined property '%s'.", field)
+	}
+	return holder.Get(token.NewToken(token.IDENTIFIER, 0, field, token.NewNullValue()))
+}
+
+// nativeSetField always fails, the only values with fields are enums and
+// their variants, which are immutable.
+func nativeSetField(interp *Interpreter, args []any) (any, error) {
+	holder, err := fieldHolder("setField", args[0])
+	if err != nil {
+		return nil, err
+	}
+	field, err := fieldName("setField", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if !hasField(holder, field) {
+		return nil, fmt.Errorf("Undefined property '%s'.", field)
+	}
+	return nil, fmt.Errorf("Cannot set field '%s' of %s, enums are immutable.", field, stringify(holder))
+}
+
+func defineReflectGlobals(env *environment.Environment) {
+	env.Define("type", NewNativeFunction("type", 1, nativeType))
+	env.Define("arity", NewNativeFunction("arity", 1, nativeArity))
+	env.Define("name", NewNativeFunction("name", 1, nativeName))
+	env.Define("source", NewNativeFunction("source", 1, nativeSource))
+	env.Define("fields", NewNativeFunction("fields", 1, nativeFields))
+	env.Define("methods", NewNativeFunction("methods", 1, nativeMethods))
+	env.Define("hasField", NewNativeFunction("hasField", 2, nativeHasField))
+	env.Define("getField", NewNativeFunction("getField", 2, nativeGetField))
+	env.Define("setField", NewNativeFunction("setField", 3, nativeSetField))
+}
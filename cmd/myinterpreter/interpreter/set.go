@@ -0,0 +1,206 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/environment"
+)
+
+// Set keeps its elements in insertion order so iterating and printing
+// are deterministic.
+type Set struct {
+	mu sync.RWMutex
+	// index maps the hashKey of every element to its position.
+	index    map[any]int
+	elements []any
+}
+
+func (s *Set) add(v any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := hashKey(v)
+	if _, ok := s.index[key]; ok {
+		return
+	}
+	s.index[key] = len(s.elements)
+	s.elements = append(s.elements, v)
+}
+
+func (s *Set) remove(v any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := hashKey(v)
+	idx, ok := s.index[key]
+	if !ok {
+		return false
+	}
+	delete(s.index, key)
+	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
+	for i := idx; i < len(s.elements); i++ {
+		s.index[hashKey(s.elements[i])] = i
+	}
+	return true
+}
+
+func (s *Set) has(v any) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.index[hashKey(v)]
+	return ok
+}
+
+func (s *Set) size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.elements)
+}
+
+// values returns a copy of the elements, so callers can keep iterating
+// while the set is modified.
+func (s *Set) values() []any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]any{}, s.elements...)
+}
+
+func (s *Set) Iterator() Iterator {
+	return &sliceIterator{values: s.values()}
+}
+
+func (s *Set) String() string {
+	return s.format(map[any]bool{})
+}
+
+func (s *Set) format(seen map[any]bool) string {
+	if seen[s] {
+		return "#{...}"
+	}
+	seen[s] = true
+	defer delete(seen, s)
+	values := s.values()
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = reprIn(v, seen)
+	}
+	return fmt.Sprintf("#{%s}", strings.Join(parts, ", "))
+}
+
+func NewSet(values ...any) *Set {
+	s := &Set{
+		index: map[any]int{},
+	}
+	for _, v := range values {
+		s.add(v)
+	}
+	return s
+}
+
+func union(a *Set, b *Set) *Set {
+	return NewSet(append(a.values(), b.values()...)...)
+}
+
+func intersection(a *Set, b *Set) *Set {
+	s := NewSet()
+	for _, v := range a.values() {
+		if b.has(v) {
+			s.add(v)
+		}
+	}
+	return s
+}
+
+func difference(a *Set, b *Set) *Set {
+	s := NewSet()
+	for _, v := range a.values() {
+		if !b.has(v) {
+			s.add(v)
+		}
+	}
+	return s
+}
+
+type sliceIterator struct {
+	values []any
+	idx    int
+}
+
+func (it *sliceIterator) Next() (any, bool) {
+	if it.idx >= len(it.values) {
+		return nil, false
+	}
+	it.idx++
+	return it.values[it.idx-1], true
+}
+
+// repr is stringify for values nested in a collection, strings are quoted
+// so `#{"1", 1}` doesn't print as two ones.
+func repr(v any) string {
+	return reprIn(v, map[any]bool{})
+}
+
+// reprIn is repr for a value nested in the collections of seen, which are
+// being printed already. Sets are mutable and can contain themselves, a
+// collection that comes up again is printed as a placeholder.
+func reprIn(v any, seen map[any]bool) string {
+	switch t := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", t)
+	case *Set:
+		return t.format(seen)
+	case *Tuple:
+		return t.format(seen)
+	}
+	return stringify(v)
+}
+
+func setMethod(name string, arity int, fn func(this *Set, args []any) (any, error)) *NativeMethod {
+	return &NativeMethod{
+		name:  name,
+		arity: arity,
+		fn: func(interp *Interpreter, this any, args []any) (any, error) {
+			return fn(this.(*Set), args)
+		},
+	}
+}
+
+var setMethods = map[string]*NativeMethod{
+	"size": setMethod("size", 0, func(this *Set, args []any) (any, error) {
+		return float64(this.size()), nil
+	}),
+	"add": setMethod("add", 1, func(this *Set, args []any) (any, error) {
+		this.add(args[0])
+		return nil, nil
+	}),
+	"remove": setMethod("remove", 1, func(this *Set, args []any) (any, error) {
+		return this.remove(args[0]), nil
+	}),
+	"contains": setMethod("contains", 1, func(this *Set, args []any) (any, error) {
+		return this.has(args[0]), nil
+	}),
+}
+
+// nativeSet is set() for an empty set or set(iterable) to collect the
+// elements of a range, string, bytes, enum or other set.
+func nativeSet(interp *Interpreter, args []any) (any, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("Expected 0 to 1 arguments but got %v.", len(args))
+	}
+	s := NewSet()
+	if len(args) == 0 {
+		return s, nil
+	}
+	it, ok := iterate(args[0])
+	if !ok {
+		return nil, fmt.Errorf("Argument to 'set' must be a range, string, bytes, enum, set or tuple.")
+	}
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		s.add(v)
+	}
+	return s, nil
+}
+
+func defineSetGlobals(env *environment.Environment) {
+	env.Define("set", NewNativeFunction("set", variadicArity, nativeSet))
+}
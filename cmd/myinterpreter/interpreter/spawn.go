@@ -0,0 +1,99 @@
+package interpreter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// taskGroup tracks spawned tasks. Its lock also guards every channel of the
+// group, so blocking and waking tasks is seen in one place.
+type taskGroup struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+	// running counts spawned tasks that have not finished yet, the main task
+	// is always running unless it is in wait.
+	running int
+	waiting bool
+	blocked []*waiter
+}
+
+// spawn runs function on its own goroutine using interp, which must not be
+// shared with any other task.
+func (t *taskGroup) spawn(interp *Interpreter, keywoard *token.Token, function Callable, args []any) {
+	t.wg.Add(1)
+	t.mu.Lock()
+	t.running++
+	t.mu.Unlock()
+	go func() {
+		defer t.wg.Done()
+		_, err := function.Call(interp, args)
+		if err != nil {
+			interp.onError(wrapRuntimeError(keywoard, err))
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if interp.isErrorOcured() {
+			t.errs = append(t.errs, interp.errs...)
+		}
+		t.running--
+		t.checkDeadlock()
+	}()
+}
+
+// wait blocks until every spawned task has finished and returns the errors
+// they produced.
+func (t *taskGroup) wait() []error {
+	t.mu.Lock()
+	t.waiting = true
+	t.checkDeadlock()
+	t.mu.Unlock()
+	t.wg.Wait()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.waiting = false
+	errs := t.errs
+	t.errs = nil
+	return errs
+}
+
+// park marks w as blocked, t.mu must be held.
+func (t *taskGroup) park(w *waiter) {
+	t.blocked = append(t.blocked, w)
+	t.checkDeadlock()
+}
+
+// fire completes the operation of w with a value received from, or an
+// error, and wakes its task. t.mu must be held.
+func (t *taskGroup) fire(w *waiter, from *Channel, value any, err error) {
+	for idx, b := range t.blocked {
+		if b == w {
+			t.blocked = append(t.blocked[:idx], t.blocked[idx+1:]...)
+			break
+		}
+	}
+	w.from = from
+	w.value = value
+	w.err = err
+	w.fired = true
+	close(w.done)
+}
+
+// checkDeadlock wakes every blocked task with an error once no task is left
+// that could unblock them. t.mu must be held.
+func (t *taskGroup) checkDeadlock() {
+	stuck := len(t.blocked)
+	if t.waiting {
+		stuck++
+	}
+	if len(t.blocked) == 0 || stuck < t.running+1 {
+		return
+	}
+	blocked := t.blocked
+	t.blocked = nil
+	for _, w := range blocked {
+		t.fire(w, nil, nil, fmt.Errorf("Deadlock: all tasks are blocked."))
+	}
+}
@@ -0,0 +1,140 @@
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tuple is an immutable sequence of values, compared and hashed by its
+// elements.
+type Tuple struct {
+	elements []any
+}
+
+func (t *Tuple) Iterator() Iterator {
+	return &sliceIterator{values: t.elements}
+}
+
+func (t *Tuple) String() string {
+	return t.format(map[any]bool{})
+}
+
+func (t *Tuple) format(seen map[any]bool) string {
+	if seen[t] {
+		return "(...)"
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	parts := make([]string, len(t.elements))
+	for i, v := range t.elements {
+		parts[i] = reprIn(v, seen)
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf("(%s,)", parts[0])
+	}
+	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+}
+
+func NewTuple(elements []any) *Tuple {
+	return &Tuple{
+		elements: elements,
+	}
+}
+
+// isEqual is the equality of ==, tuples are equal when their elements are
+// and sets when they hold equal elements.
+func isEqual(a any, b any) bool {
+	return equal(a, b, map[[2]any]bool{})
+}
+
+// equal is isEqual, pairs holds the collections being compared already. A
+// set can contain itself, so a pair that comes up again is taken as equal
+// and the other elements decide.
+func equal(a any, b any, pairs map[[2]any]bool) bool {
+	switch ta := a.(type) {
+	case *Tuple:
+		tb, ok := b.(*Tuple)
+		if !ok || len(ta.elements) != len(tb.elements) {
+			return false
+		}
+		if ta == tb || pairs[[2]any{ta, tb}] {
+			return true
+		}
+		pairs[[2]any{ta, tb}] = true
+		for i := range ta.elements {
+			if !equal(ta.elements[i], tb.elements[i], pairs) {
+				return false
+			}
+		}
+		return true
+	case *Set:
+		sb, ok := b.(*Set)
+		if !ok || ta.size() != sb.size() {
+			return false
+		}
+		if ta == sb || pairs[[2]any{ta, sb}] {
+			return true
+		}
+		pairs[[2]any{ta, sb}] = true
+		return subset(ta, sb, pairs) && subset(sb, ta, pairs)
+	}
+	return a == b
+}
+
+// subset reports whether every element of a has an equal element in b.
+func subset(a *Set, b *Set, pairs map[[2]any]bool) bool {
+	values := b.values()
+	for _, v := range a.values() {
+		if b.has(v) {
+			continue
+		}
+		found := false
+		for _, w := range values {
+			if equal(v, w, pairs) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type tupleKey string
+
+// hashKey returns the map key of v such that equal values share a key.
+func hashKey(v any) any {
+	t, ok := v.(*Tuple)
+	if !ok {
+		return v
+	}
+	var b strings.Builder
+	writeKey(&b, t)
+	return tupleKey(b.String())
+}
+
+func writeKey(b *strings.Builder, v any) {
+	switch t := v.(type) {
+	case *Tuple:
+		b.WriteString("(")
+		for _, e := range t.elements {
+			writeKey(b, e)
+			b.WriteString(",")
+		}
+		b.WriteString(")")
+	case string:
+		b.WriteString("s" + strconv.Quote(t))
+	case float64:
+		b.WriteString("n" + strconv.FormatFloat(t, 'g', -1, 64))
+	case bool:
+		b.WriteString("b" + strconv.FormatBool(t))
+	case nil:
+		b.WriteString("nil")
+	default:
+		// every other value is a pointer and compared by identity
+		fmt.Fprintf(b, "p%p", t)
+	}
+}
@@ -37,8 +37,14 @@ func (l *Lexer) Lex() []error {
 			l.addToken(token.NewToken(token.LEFT_BRACE, l.line, "{", token.NewNullValue()))
 		case '}':
 			l.addToken(token.NewToken(token.RIGHT_BRACE, l.line, "}", token.NewNullValue()))
+		case '[':
+			l.addToken(token.NewToken(token.LEFT_BRACKET, l.line, "[", token.NewNullValue()))
+		case ']':
+			l.addToken(token.NewToken(token.RIGHT_BRACKET, l.line, "]", token.NewNullValue()))
 		case ';':
 			l.addToken(token.NewToken(token.SEMICOLON, l.line, ";", token.NewNullValue()))
+		case ':':
+			l.addToken(token.NewToken(token.COLON, l.line, ":", token.NewNullValue()))
 		case ',':
 			l.addToken(token.NewToken(token.COMMA, l.line, ",", token.NewNullValue()))
 		case '+':
@@ -47,6 +53,22 @@ func (l *Lexer) Lex() []error {
 			l.addToken(token.NewToken(token.MINUS, l.line, "-", token.NewNullValue()))
 		case '*':
 			l.addToken(token.NewToken(token.STAR, l.line, "*", token.NewNullValue()))
+		case '|':
+			if l.matchCur('>') {
+				l.addToken(token.NewToken(token.PIPE_GREATER, l.line, "|>", token.NewNullValue()))
+			} else {
+				l.addToken(token.NewToken(token.PIPE, l.line, "|", token.NewNullValue()))
+			}
+		case '&':
+			l.addToken(token.NewToken(token.AMPERSAND, l.line, "&", token.NewNullValue()))
+		case '@':
+			l.addToken(token.NewToken(token.AT, l.line, "@", token.NewNullValue()))
+		case '#':
+			if !l.matchCur('{') {
+				l.onError(NewLexError(l.line, "Unexpected character", string(char)))
+				continue
+			}
+			l.addToken(token.NewToken(token.HASH_BRACE, l.line, "#{", token.NewNullValue()))
 		case '!':
 			if l.matchCur('=') {
 				l.addToken(token.NewToken(token.BANG_EQUAL, l.line, "!=", token.NewNullValue()))
@@ -56,6 +78,8 @@ func (l *Lexer) Lex() []error {
 		case '=':
 			if l.matchCur('=') {
 				l.addToken(token.NewToken(token.EQUAL_EQUAL, l.line, "==", token.NewNullValue()))
+			} else if l.matchCur('>') {
+				l.addToken(token.NewToken(token.EQUAL_GREATER, l.line, "=>", token.NewNullValue()))
 			} else {
 				l.addToken(token.NewToken(token.EQUAL, l.line, "=", token.NewNullValue()))
 			}
@@ -71,6 +95,16 @@ func (l *Lexer) Lex() []error {
 			} else {
 				l.addToken(token.NewToken(token.GREATER, l.line, ">", token.NewNullValue()))
 			}
+		case '?':
+			if l.matchCur('?') {
+				l.addToken(token.NewToken(token.QUESTION_QUESTION, l.line, "??", token.NewNullValue()))
+			} else if l.matchCur('.') {
+				l.addToken(token.NewToken(token.QUESTION_DOT, l.line, "?.", token.NewNullValue()))
+			} else if l.matchCur('[') {
+				l.addToken(token.NewToken(token.QUESTION_BRACKET, l.line, "?[", token.NewNullValue()))
+			} else {
+				l.addToken(token.NewToken(token.QUESTION, l.line, "?", token.NewNullValue()))
+			}
 		case '/':
 			//match comment
 			if l.matchCur('/') {
@@ -81,7 +115,15 @@ func (l *Lexer) Lex() []error {
 			}
 			l.addToken(token.NewToken(token.SLASH, l.line, "/", token.NewNullValue()))
 		case '.':
-			l.addToken(token.NewToken(token.DOT, l.line, ".", token.NewNullValue()))
+			if l.matchCur('.') {
+				if l.matchCur('=') {
+					l.addToken(token.NewToken(token.DOT_DOT_EQUAL, l.line, "..=", token.NewNullValue()))
+				} else {
+					l.addToken(token.NewToken(token.DOT_DOT, l.line, "..", token.NewNullValue()))
+				}
+			} else {
+				l.addToken(token.NewToken(token.DOT, l.line, ".", token.NewNullValue()))
+			}
 		case '"':
 			token, err := l.lexString()
 			if err != nil {
@@ -95,6 +137,15 @@ func (l *Lexer) Lex() []error {
 		case '\n':
 			l.line++
 		default:
+			if char == 'b' && l.peek() == '"' {
+				token, err := l.lexBytes()
+				if err != nil {
+					l.onError(err)
+					continue
+				}
+				l.addToken(token)
+				continue
+			}
 			if isAlpha(char) {
 				token, err := l.lexIdent()
 				if err != nil {
@@ -120,7 +171,11 @@ func (l *Lexer) Lex() []error {
 	return l.getErrors()
 }
 
+// addToken is called once the last character of t is consumed, so t
+// starts len(t.Text) bytes back.
 func (l *Lexer) addToken(t *token.Token) {
+	t.Offset = l.end - len(t.Text)
+	t.Source = &l.source
 	l.tokens = append(l.tokens, t)
 }
 
@@ -153,6 +208,71 @@ func (l *Lexer) lexString() (*token.Token, error) {
 	return token.NewToken(token.STRING, startLine, l.source[l.start:l.end], token.NewStringValue(l.source[l.start+1:l.end-1])), nil
 }
 
+var byteEscapes = map[byte]byte{
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+	'0':  0,
+	'\\': '\\',
+	'"':  '"',
+}
+
+// lexBytes lexes a `b"..."` literal, unlike strings it understands escapes
+// and `\xHH` for any byte.
+func (l *Lexer) lexBytes() (*token.Token, error) {
+	l.start = l.end - 1
+	startLine := l.line
+	// consume the opening '"'
+	l.advance()
+	var b strings.Builder
+	for l.hasNext() && l.peek() != '"' {
+		cur := l.advance()
+		if cur == '\n' {
+			l.line++
+		}
+		if cur != '\\' {
+			b.WriteByte(cur)
+			continue
+		}
+		if !l.hasNext() {
+			break
+		}
+		esc := l.advance()
+		if c, ok := byteEscapes[esc]; ok {
+			b.WriteByte(c)
+			continue
+		}
+		if esc == 'x' && l.end+2 <= len(l.source) {
+			n, err := strconv.ParseUint(l.source[l.end:l.end+2], 16, 8)
+			if err == nil {
+				l.end += 2
+				b.WriteByte(byte(n))
+				continue
+			}
+		}
+		l.skipBytes()
+		return nil, NewLexError(l.line, "Invalid escape sequence in bytes literal", "\\"+string(esc))
+	}
+	if !l.hasNext() {
+		return nil, NewLexError(startLine, "Unterminated bytes literal.", "")
+	}
+	l.advance()
+	return token.NewToken(token.BYTES, startLine, l.source[l.start:l.end], token.NewBytesValue(b.String())), nil
+}
+
+// skipBytes moves past the rest of a bad bytes literal so lexing goes on
+// after it.
+func (l *Lexer) skipBytes() {
+	for l.hasNext() && l.peek() != '"' {
+		if l.advance() == '\\' && l.hasNext() {
+			l.advance()
+		}
+	}
+	if l.hasNext() {
+		l.advance()
+	}
+}
+
 func (l *Lexer) lexNumber() (*token.Token, error) {
 	//start of substr should start from prev advanced token
 	l.start = l.end - 1
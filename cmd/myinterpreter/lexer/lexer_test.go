@@ -25,23 +25,35 @@ func TestLexerNumbers(t *testing.T) {
 		},
 		{
 			name:  "keywoards",
-			input: `and class else false for fun if nil or return super this true var while print`,
+			input: `and assert break class continue defer do else enum false for fun if in loop macro match nil or return spawn super this true var while with print`,
 			expectedLines: []string{
 				"AND and null",
+				"ASSERT assert null",
+				"BREAK break null",
 				"CLASS class null",
+				"CONTINUE continue null",
+				"DEFER defer null",
+				"DO do null",
 				"ELSE else null",
+				"ENUM enum null",
 				"FALSE false null",
 				"FOR for null",
 				"FUN fun null",
 				"IF if null",
+				"IN in null",
+				"LOOP loop null",
+				"MACRO macro null",
+				"MATCH match null",
 				"NIL nil null",
 				"OR or null",
 				"RETURN return null",
+				"SPAWN spawn null",
 				"SUPER super null",
 				"THIS this null",
 				"TRUE true null",
 				"VAR var null",
 				"WHILE while null",
+				"WITH with null",
 				"PRINT print null",
 				"EOF  null",
 			},
@@ -80,7 +92,7 @@ end`,
 		},
 		{
 			name:  "punctuators",
-			input: `(){};,+-*!===<=>=!=<>/.`,
+			input: `(){};,+-*!===<=>=!=<>/.=>:?`,
 			expectedLines: []string{
 				"LEFT_PAREN ( null",
 				"RIGHT_PAREN ) null",
@@ -100,6 +112,96 @@ end`,
 				"GREATER > null",
 				"SLASH / null",
 				"DOT . null",
+				"EQUAL_GREATER => null",
+				"COLON : null",
+				"QUESTION ? null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "ranges",
+			input: `1..3 1..=3 s[0]`,
+			expectedLines: []string{
+				"NUMBER 1 1.0",
+				"DOT_DOT .. null",
+				"NUMBER 3 3.0",
+				"NUMBER 1 1.0",
+				"DOT_DOT_EQUAL ..= null",
+				"NUMBER 3 3.0",
+				"IDENTIFIER s null",
+				"LEFT_BRACKET [ null",
+				"NUMBER 0 0.0",
+				"RIGHT_BRACKET ] null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "sets",
+			input: `#{1} | a & b #`,
+			expectedLines: []string{
+				"HASH_BRACE #{ null",
+				"NUMBER 1 1.0",
+				"RIGHT_BRACE } null",
+				"PIPE | null",
+				"IDENTIFIER a null",
+				"AMPERSAND & null",
+				"IDENTIFIER b null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "decorators",
+			input: `@memo fun`,
+			expectedLines: []string{
+				"AT @ null",
+				"IDENTIFIER memo null",
+				"FUN fun null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "pipeline",
+			input: `x |> f | g`,
+			expectedLines: []string{
+				"IDENTIFIER x null",
+				"PIPE_GREATER |> null",
+				"IDENTIFIER f null",
+				"PIPE | null",
+				"IDENTIFIER g null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "bytes",
+			input: `b"\x00\xffhi\n" b"" be b"\q" 1`,
+			expectedLines: []string{
+				`BYTES b"\x00\xffhi\n" 00ff68690a`,
+				`BYTES b"" `,
+				"IDENTIFIER be null",
+				"NUMBER 1 1.0",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "nil coalescing",
+			input: `a ?? b`,
+			expectedLines: []string{
+				"IDENTIFIER a null",
+				"QUESTION_QUESTION ?? null",
+				"IDENTIFIER b null",
+				"EOF  null",
+			},
+		},
+		{
+			name:  "optional chaining",
+			input: `a?.b?[0]`,
+			expectedLines: []string{
+				"IDENTIFIER a null",
+				"QUESTION_DOT ?. null",
+				"IDENTIFIER b null",
+				"QUESTION_BRACKET ?[ null",
+				"NUMBER 0 0.0",
+				"RIGHT_BRACKET ] null",
 				"EOF  null",
 			},
 		},
@@ -0,0 +1,23 @@
+package macro
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+type MacroError struct {
+	t       *token.Token
+	message string
+}
+
+func NewMacroError(t *token.Token, message string) *MacroError {
+	return &MacroError{
+		t:       t,
+		message: message,
+	}
+}
+
+func (e MacroError) Error() string {
+	return fmt.Sprintf("[line %v] Macro error: %s", e.t.Line, e.message)
+}
@@ -0,0 +1,671 @@
+package macro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expression"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/interpreter"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/stmt"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// maxDepth bounds nested expansion so a macro expanding to itself is an
+// error instead of a hang.
+const maxDepth = 100
+
+// Expander replaces every macro call of a program with its expansion. It
+// runs between parsing and checking or interpreting. A template macro is
+// quoted as a whole and its parameters are its only unquotes. The body of
+// a procedural macro runs instead and computes the code it expands to.
+type Expander struct {
+	macros map[string]*stmt.MacroStmt
+	// frame is the expansion being built, nil outside of templates.
+	frame *frame
+	depth int
+	// collecting is set for the first walk over a template, which only
+	// records the names it declares.
+	collecting bool
+	// loops are the loops enclosing the statement being expanded, argLoops
+	// is how many of them enclose the macro argument being expanded.
+	loops    []*loopScope
+	argLoops int
+	gensym   int
+	// interp runs the bodies of procedural macros, values are the values
+	// of the unquotes of the quoted code being spliced.
+	interp  *interpreter.Interpreter
+	values  map[*expression.UnquoteExpression]any
+	outExp  expression.Expression
+	outStmt stmt.Stmt
+	errs    []error
+}
+
+// frame is a single macro call being expanded.
+type frame struct {
+	call *token.Token
+	args map[string]*expression.MacroArg
+	// renames maps every name declared in the template to a fresh name,
+	// so it can't capture or shadow the caller's variables. labels does the
+	// same for loop labels, which live in their own namespace.
+	renames map[string]string
+	labels  map[string]string
+}
+
+// loopScope is a loop being expanded. A break or continue without a label
+// in a macro argument gets the label of its loop, so it can't be captured
+// by a loop of the template the argument is spliced into.
+type loopScope struct {
+	label *token.Token
+}
+
+func New() *Expander {
+	return &Expander{
+		macros:   map[string]*stmt.MacroStmt{},
+		argLoops: -1,
+	}
+}
+
+// Expand returns program with its macro declarations removed and every
+// macro call expanded.
+func (e *Expander) Expand(program []stmt.Stmt) ([]stmt.Stmt, []error) {
+	for _, s := range program {
+		if m, ok := s.(*stmt.MacroStmt); ok {
+			if _, ok := e.macros[m.Name.Text]; ok {
+				e.onError(m.Name, fmt.Sprintf("Macro '%s' is already declared.", m.Name.Text))
+			}
+			e.macros[m.Name.Text] = m
+		}
+	}
+	out := []stmt.Stmt{}
+	for _, s := range program {
+		if _, ok := s.(*stmt.MacroStmt); ok {
+			continue
+		}
+		out = append(out, e.expandStmt(s))
+	}
+	return out, e.errs
+}
+
+func (e *Expander) onError(t *token.Token, message string) {
+	e.errs = append(e.errs, NewMacroError(t, message))
+}
+
+func (e *Expander) expandExp(exp expression.Expression) expression.Expression {
+	if exp == nil {
+		return nil
+	}
+	exp.Accept(e)
+	return e.outExp
+}
+
+func (e *Expander) expandExps(exps []expression.Expression) []expression.Expression {
+	out := make([]expression.Expression, len(exps))
+	for i, exp := range exps {
+		out[i] = e.expandExp(exp)
+	}
+	return out
+}
+
+func (e *Expander) expandStmt(s stmt.Stmt) stmt.Stmt {
+	if s == nil {
+		return nil
+	}
+	s.Accept(e)
+	return e.outStmt
+}
+
+func (e *Expander) expandStmts(stmts []stmt.Stmt) []stmt.Stmt {
+	out := make([]stmt.Stmt, len(stmts))
+	for i, s := range stmts {
+		out[i] = e.expandStmt(s)
+	}
+	return out
+}
+
+// tok copies a template token with the line of the call, so errors in
+// the expansion point at the call site.
+func (e *Expander) tok(t *token.Token) *token.Token {
+	if e.frame == nil || t == nil {
+		return t
+	}
+	return token.NewToken(t.Type, e.frame.call.Line, t.Text, t.TokenValue)
+}
+
+// name is tok for identifiers, names the template declares are renamed.
+func (e *Expander) name(t *token.Token) *token.Token {
+	t = e.tok(t)
+	if e.frame == nil {
+		return t
+	}
+	if renamed, ok := e.frame.renames[t.Text]; ok {
+		t.Text = renamed
+	}
+	return t
+}
+
+// declare is name for the identifier of a declaration. The fresh names
+// contain '#', which the lexer never puts in an identifier.
+func (e *Expander) declare(t *token.Token) *token.Token {
+	if e.frame != nil {
+		if _, ok := e.frame.renames[t.Text]; !ok {
+			e.gensym++
+			e.frame.renames[t.Text] = fmt.Sprintf("%s#%d", t.Text, e.gensym)
+		}
+	}
+	return e.name(t)
+}
+
+// label is tok for a loop label, labels of template loops are renamed
+// like declared names.
+func (e *Expander) label(t *token.Token) *token.Token {
+	if t == nil {
+		return nil
+	}
+	t = e.tok(t)
+	if e.frame == nil {
+		return t
+	}
+	if _, ok := e.frame.labels[t.Text]; !ok {
+		e.gensym++
+		e.frame.labels[t.Text] = fmt.Sprintf("%s#%d", t.Text, e.gensym)
+	}
+	t.Text = e.frame.labels[t.Text]
+	return t
+}
+
+// loop expands the body of a loop labeled label and returns the label
+// the expanded loop gets, a fresh one when a jump in a macro argument
+// targets the loop.
+func (e *Expander) loop(label *token.Token, body func()) *token.Token {
+	scope := &loopScope{label: e.label(label)}
+	e.loops = append(e.loops, scope)
+	body()
+	e.loops = e.loops[:len(e.loops)-1]
+	return scope.label
+}
+
+// jumpLabel returns the label of a break or continue. A jump without a
+// label in a macro argument targets the innermost loop of the caller.
+func (e *Expander) jumpLabel(keywoard *token.Token, label *token.Token) *token.Token {
+	if label != nil {
+		if e.frame == nil {
+			return label
+		}
+		t := e.tok(label)
+		if renamed, ok := e.frame.labels[t.Text]; ok {
+			t.Text = renamed
+		}
+		return t
+	}
+	if e.collecting || len(e.loops) == 0 || len(e.loops) != e.argLoops {
+		return nil
+	}
+	scope := e.loops[len(e.loops)-1]
+	if scope.label == nil {
+		e.gensym++
+		scope.label = token.NewToken(token.IDENTIFIER, keywoard.Line, fmt.Sprintf("loop#%d", e.gensym), token.NewNullValue())
+	}
+	return scope.label
+}
+
+func (e *Expander) declareAll(names []*token.Token) []*token.Token {
+	out := make([]*token.Token, len(names))
+	for i, n := range names {
+		out[i] = e.declare(n)
+	}
+	return out
+}
+
+// arg returns the argument bound to a parameter of the expanding macro.
+func (e *Expander) arg(name *token.Token) (*expression.MacroArg, bool) {
+	if e.frame == nil {
+		return nil, false
+	}
+	a, ok := e.frame.args[name.Text]
+	return a, ok
+}
+
+// instantiate expands the template of the macro called at call. The
+// arguments are expanded in the caller's frame first, so the template
+// can't rename or substitute inside them.
+func (e *Expander) instantiate(call *expression.MacroCallExpression) ([]stmt.Stmt, bool) {
+	name := e.tok(call.Name)
+	m, ok := e.macros[name.Text]
+	if !ok {
+		e.onError(name, fmt.Sprintf("Undefined macro '%s'.", name.Text))
+		return nil, false
+	}
+	if len(call.Args) != len(m.Params) {
+		e.onError(name, fmt.Sprintf("Macro '%s' expects %v arguments but got %v.", m.Name.Text, len(m.Params), len(call.Args)))
+		return nil, false
+	}
+	if e.depth >= maxDepth {
+		e.onError(name, "Macro expansion is too deep.")
+		return nil, false
+	}
+	args := map[string]*expression.MacroArg{}
+	fragments := make([]any, len(call.Args))
+	argLoops := e.argLoops
+	e.argLoops = len(e.loops)
+	for idx, a := range call.Args {
+		if a.Exp != nil {
+			exp := e.expandExp(a.Exp)
+			args[m.Params[idx].Text] = &expression.MacroArg{Exp: exp}
+			fragments[idx] = &interpreter.Fragment{Exp: exp}
+		} else {
+			block := e.expandStmt(a.Block.(*stmt.BlockStmt))
+			args[m.Params[idx].Text] = &expression.MacroArg{Block: block}
+			fragments[idx] = &interpreter.Fragment{Stmts: block.(*stmt.BlockStmt).Statements}
+		}
+	}
+	e.argLoops = argLoops
+	if m.Procedural {
+		return e.run(name, m, fragments)
+	}
+	return e.walk(name, args, func() []stmt.Stmt {
+		return e.expandStmts(m.Body)
+	}), true
+}
+
+// run expands a call of a procedural macro: the body runs with the
+// arguments as code and the code it returns is spliced in.
+func (e *Expander) run(name *token.Token, m *stmt.MacroStmt, args []any) ([]stmt.Stmt, bool) {
+	if e.interp == nil {
+		e.interp = interpreter.New()
+	}
+	result, errs := e.interp.RunMacro(m, args)
+	if errs != nil {
+		e.onError(name, fmt.Sprintf("Macro '%s' failed: %s", m.Name.Text, strings.ReplaceAll(errs[0].Error(), "\n", " ")))
+		return nil, false
+	}
+	code, ok := result.(*interpreter.Fragment)
+	if !ok {
+		e.onError(name, fmt.Sprintf("Macro '%s' must return quoted code.", m.Name.Text))
+		return nil, false
+	}
+	return e.walk(name, nil, func() []stmt.Stmt {
+		return e.spliceStmts(code)
+	}), true
+}
+
+// walk builds an expansion in a frame of its own.
+func (e *Expander) walk(call *token.Token, args map[string]*expression.MacroArg, build func() []stmt.Stmt) []stmt.Stmt {
+	enclosing, collecting, loops, argLoops := e.frame, e.collecting, e.loops, e.argLoops
+	e.frame = &frame{call: call, args: args, renames: map[string]string{}, labels: map[string]string{}}
+	// the loops of the caller don't enclose the template
+	e.loops, e.argLoops = nil, -1
+	e.depth++
+	// the first walk only collects the declared names, so uses that come
+	// before a declaration, like in a function declared after its caller,
+	// are renamed as well
+	errs := e.errs
+	e.collecting = true
+	build()
+	e.errs = errs
+	e.collecting = false
+	body := build()
+	e.depth--
+	e.frame, e.collecting, e.loops, e.argLoops = enclosing, collecting, loops, argLoops
+	return body
+}
+
+// spliceStmts returns the statements of a code value, an expression is a
+// single expression statement. Quoted code is expanded in the current
+// frame, code of the caller is already expanded and is kept as is.
+func (e *Expander) spliceStmts(f *interpreter.Fragment) []stmt.Stmt {
+	if f.Exp != nil {
+		return []stmt.Stmt{stmt.NewExpressionStmt(e.spliceExp(f))}
+	}
+	if !f.Quoted {
+		return f.Stmts
+	}
+	values := e.values
+	e.values = f.Values
+	out := e.expandStmts(f.Stmts)
+	e.values = values
+	return out
+}
+
+func (e *Expander) spliceExp(f *interpreter.Fragment) expression.Expression {
+	if !f.Quoted {
+		return f.Exp
+	}
+	values := e.values
+	e.values = f.Values
+	out := e.expandExp(f.Exp)
+	e.values = values
+	return out
+}
+
+func (e *Expander) VisitExpressionStmt(s *stmt.ExpressionStmt) {
+	switch exp := s.Exp.(type) {
+	case *expression.VarExpression:
+		if a, ok := e.arg(exp.Name); ok && a.Block != nil {
+			e.outStmt = a.Block.(stmt.Stmt)
+			return
+		}
+	case *expression.UnquoteExpression:
+		if f, ok := e.values[exp].(*interpreter.Fragment); ok && f.Exp == nil {
+			e.outStmt = stmt.NewBlockStmt(e.spliceStmts(f))
+			return
+		}
+	case *expression.MacroCallExpression:
+		if e.collecting {
+			break
+		}
+		body, ok := e.instantiate(exp)
+		if !ok {
+			e.outStmt = s
+			return
+		}
+		e.outStmt = stmt.NewBlockStmt(body)
+		return
+	}
+	e.outStmt = stmt.NewExpressionStmt(e.expandExp(s.Exp))
+}
+
+func (e *Expander) VisitPrintStmt(s *stmt.PrintStmt) {
+	e.outStmt = stmt.NewPrintStmt(e.expandExp(s.Exp))
+}
+
+func (e *Expander) VisitVarStmt(s *stmt.VarStmt) {
+	init := e.expandExp(s.Init)
+	e.outStmt = stmt.NewVarStmt(e.declare(s.Name), s.Type, init)
+}
+
+func (e *Expander) VisitUnpackStmt(s *stmt.UnpackStmt) {
+	init := e.expandExp(s.Init)
+	e.outStmt = stmt.NewUnpackStmt(e.declareAll(s.Names), s.Types, init)
+}
+
+func (e *Expander) VisitBlockStmt(s *stmt.BlockStmt) {
+	e.outStmt = stmt.NewBlockStmt(e.expandStmts(s.Statements))
+}
+
+func (e *Expander) VisitIfStmt(s *stmt.IfStmt) {
+	e.outStmt = stmt.NewIfStmt(e.expandExp(s.Condition), e.expandStmt(s.ThenBranch), e.expandStmt(s.ElseBranch))
+}
+
+func (e *Expander) VisitWhileStmt(s *stmt.WhileStmt) {
+	var cond, increment expression.Expression
+	var body stmt.Stmt
+	label := e.loop(s.Label, func() {
+		cond = e.expandExp(s.Condition)
+		body = e.expandStmt(s.Body)
+		increment = e.expandExp(s.Increment)
+	})
+	e.outStmt = stmt.NewWhileStmt(label, cond, body, increment)
+}
+
+func (e *Expander) VisitWithStmt(s *stmt.WithStmt) {
+	init := e.expandExp(s.Init)
+	e.outStmt = stmt.NewWithStmt(e.tok(s.Keywoard), e.declare(s.Name), init, e.expandStmts(s.Body))
+}
+
+func (e *Expander) VisitDoWhileStmt(s *stmt.DoWhileStmt) {
+	var body stmt.Stmt
+	var cond expression.Expression
+	label := e.loop(s.Label, func() {
+		body = e.expandStmt(s.Body)
+		cond = e.expandExp(s.Condition)
+	})
+	e.outStmt = stmt.NewDoWhileStmt(label, body, cond)
+}
+
+func (e *Expander) VisitLoopStmt(s *stmt.LoopStmt) {
+	var body stmt.Stmt
+	label := e.loop(s.Label, func() {
+		body = e.expandStmt(s.Body)
+	})
+	e.outStmt = stmt.NewLoopStmt(label, body)
+}
+
+func (e *Expander) VisitForInStmt(s *stmt.ForInStmt) {
+	iterable := e.expandExp(s.Iterable)
+	name := e.declare(s.Name)
+	var body stmt.Stmt
+	label := e.loop(s.Label, func() {
+		body = e.expandStmt(s.Body)
+	})
+	e.outStmt = stmt.NewForInStmt(label, name, iterable, body)
+}
+
+func (e *Expander) VisitFunctionDeclarationStmt(s *stmt.FunctionDeclarationStmt) {
+	decorators := e.expandExps(s.Decorators)
+	name := e.declare(s.Name)
+	args := e.declareAll(s.Args)
+	e.outStmt = stmt.NewFunctionDeclarationStmt(
+		name,
+		e.expandStmts(s.Body),
+		args,
+		s.ArgTypes,
+		s.ReturnType,
+		e.expandContracts(s.Requires),
+		e.expandContracts(s.Ensures),
+		decorators,
+		s.Keywoard,
+		s.RightBrace,
+	)
+}
+
+func (e *Expander) expandContracts(contracts []*stmt.Contract) []*stmt.Contract {
+	out := make([]*stmt.Contract, len(contracts))
+	for i, c := range contracts {
+		out[i] = stmt.NewContract(e.tok(c.Keywoard), e.expandExp(c.Condition), c.Source)
+	}
+	return out
+}
+
+func (e *Expander) VisitReturnStmt(s *stmt.ReturnStmt) {
+	e.outStmt = stmt.NewReturnStmt(e.tok(s.Keywoard), e.expandExp(s.Exp))
+}
+
+func (e *Expander) VisitEnumStmt(s *stmt.EnumStmt) {
+	variants := make([]*token.Token, len(s.Variants))
+	for i, v := range s.Variants {
+		variants[i] = e.tok(v)
+	}
+	e.outStmt = stmt.NewEnumStmt(e.declare(s.Name), variants)
+}
+
+func (e *Expander) VisitSpawnStmt(s *stmt.SpawnStmt) {
+	call, ok := e.expandExp(s.Call).(*expression.FunctionCallExpression)
+	if !ok {
+		e.onError(e.tok(s.Keywoard), "Can only spawn a function call.")
+		call = s.Call
+	}
+	e.outStmt = stmt.NewSpawnStmt(e.tok(s.Keywoard), call)
+}
+
+func (e *Expander) VisitAssertStmt(s *stmt.AssertStmt) {
+	e.outStmt = stmt.NewAssertStmt(e.tok(s.Keywoard), e.expandExp(s.Condition), e.expandExp(s.Message), s.Source)
+}
+
+func (e *Expander) VisitDeferStmt(s *stmt.DeferStmt) {
+	e.outStmt = stmt.NewDeferStmt(e.tok(s.Keywoard), e.expandExp(s.Exp))
+}
+
+func (e *Expander) VisitBreakStmt(s *stmt.BreakStmt) {
+	e.outStmt = stmt.NewBreakStmt(e.tok(s.Keywoard), e.jumpLabel(s.Keywoard, s.Label))
+}
+
+func (e *Expander) VisitContinueStmt(s *stmt.ContinueStmt) {
+	e.outStmt = stmt.NewContinueStmt(e.tok(s.Keywoard), e.jumpLabel(s.Keywoard, s.Label))
+}
+
+// VisitMacroStmt is only reached for declarations that aren't at the top
+// level, Expand skips the others.
+func (e *Expander) VisitMacroStmt(s *stmt.MacroStmt) {
+	e.onError(e.tok(s.Name), "Macros can only be declared at the top level.")
+	e.outStmt = stmt.NewBlockStmt(nil)
+}
+
+func (e *Expander) VisitBinary(b *expression.BinaryExpression) {
+	e.outExp = expression.NewBinaryExpression(e.expandExp(b.Lhs), e.tok(b.Op), e.expandExp(b.Rhs))
+}
+
+func (e *Expander) VisitGrouping(g *expression.GroupingExpression) {
+	e.outExp = expression.NewGroupingExpression(e.expandExp(g.Exp))
+}
+
+func (e *Expander) VisitUnary(u *expression.UnaryExpression) {
+	e.outExp = expression.NewUnaryExpression(e.tok(u.Op), e.expandExp(u.Rhs))
+}
+
+func (e *Expander) VisitLiteral(l *expression.LiteralExpression) {
+	e.outExp = expression.NewLiteralExpression(e.tok(l.Val))
+}
+
+// VisitVarExpression substitutes the arguments of the expanding macro,
+// which is the unquote of the template.
+func (e *Expander) VisitVarExpression(v *expression.VarExpression) {
+	if a, ok := e.arg(v.Name); ok {
+		if a.Exp == nil {
+			e.onError(e.frame.call, fmt.Sprintf("Macro argument '%s' is a block and can only be used as a statement.", v.Name.Text))
+			e.outExp = v
+			return
+		}
+		e.outExp = a.Exp
+		return
+	}
+	e.outExp = expression.NewVarExpression(e.name(v.Name))
+}
+
+func (e *Expander) VisitAssignmentExpression(a *expression.AssignmentExpression) {
+	val := e.expandExp(a.Val)
+	name := e.name(a.Name)
+	if arg, ok := e.arg(a.Name); ok {
+		target, isVar := arg.Exp.(*expression.VarExpression)
+		if !isVar {
+			e.onError(e.frame.call, fmt.Sprintf("Macro argument '%s' is assigned to and must be a variable.", a.Name.Text))
+			e.outExp = a
+			return
+		}
+		name = target.Name
+	}
+	e.outExp = expression.NewAssignmentExprExpression(name, val)
+}
+
+func (e *Expander) VisitLogicalExpression(l *expression.LogicalExpression) {
+	e.outExp = expression.NewLogicalExpression(e.expandExp(l.Lhs), e.tok(l.Op), e.expandExp(l.Rhs))
+}
+
+func (e *Expander) VisitFunctionCallExpression(f *expression.FunctionCallExpression) {
+	e.outExp = expression.NewFunctionCallExpression(e.expandExp(f.Callee), e.expandExps(f.Args), e.tok(f.RightParan), f.Guarded)
+}
+
+func (e *Expander) VisitMatchExpression(m *expression.MatchExpression) {
+	value := e.expandExp(m.Value)
+	arms := make([]*expression.MatchArm, len(m.Arms))
+	for i, arm := range m.Arms {
+		var pattern expression.Pattern
+		switch p := arm.Pattern.(type) {
+		case *expression.ValuePattern:
+			pattern = expression.NewValuePattern(e.expandExp(p.Exp))
+		case *expression.BindingPattern:
+			pattern = expression.NewBindingPattern(e.declare(p.Name))
+		case *expression.WildcardPattern:
+			pattern = expression.NewWildcardPattern(e.tok(p.Token))
+		}
+		arms[i] = expression.NewMatchArm(pattern, e.expandExp(arm.Guard), e.expandExp(arm.Body))
+	}
+	e.outExp = expression.NewMatchExpression(e.tok(m.Keywoard), value, arms)
+}
+
+// VisitGetExpression keeps the property name, only variables are renamed.
+func (e *Expander) VisitGetExpression(g *expression.GetExpression) {
+	e.outExp = expression.NewGetExpression(e.expandExp(g.Object), e.tok(g.Name), g.Optional, g.Guarded)
+}
+
+func (e *Expander) VisitIndexExpression(x *expression.IndexExpression) {
+	e.outExp = expression.NewIndexExpression(e.expandExp(x.Object), e.tok(x.Bracket), e.expandExp(x.Index), x.Optional, x.Guarded)
+}
+
+func (e *Expander) VisitSetExpression(s *expression.SetExpression) {
+	e.outExp = expression.NewSetExpression(e.tok(s.Brace), e.expandExps(s.Elements))
+}
+
+func (e *Expander) VisitTupleExpression(t *expression.TupleExpression) {
+	e.outExp = expression.NewTupleExpression(e.tok(t.Paren), e.expandExps(t.Elements))
+}
+
+func (e *Expander) VisitComprehensionExpression(c *expression.ComprehensionExpression) {
+	clauses := make([]*expression.ComprehensionClause, len(c.Clauses))
+	for i, clause := range c.Clauses {
+		if clause.Keywoard.Type == token.IF {
+			clauses[i] = expression.NewIfClause(e.tok(clause.Keywoard), e.expandExp(clause.Condition))
+			continue
+		}
+		iterable := e.expandExp(clause.Iterable)
+		clauses[i] = expression.NewForClause(e.tok(clause.Keywoard), e.declareAll(clause.Names), iterable)
+	}
+	e.outExp = expression.NewComprehensionExpression(e.tok(c.Brace), e.expandExp(c.Element), clauses)
+}
+
+func (e *Expander) VisitPipelineExpression(p *expression.PipelineExpression) {
+	e.outExp = expression.NewPipelineExpression(e.expandExp(p.Lhs), e.tok(p.Op), e.expandExp(p.Rhs))
+}
+
+// VisitMacroCallExpression expands a call in expression position, the
+// template must then be a single expression statement.
+func (e *Expander) VisitMacroCallExpression(c *expression.MacroCallExpression) {
+	if e.collecting {
+		e.outExp = c
+		return
+	}
+	body, ok := e.instantiate(c)
+	if !ok {
+		e.outExp = c
+		return
+	}
+	if len(body) == 1 {
+		if s, ok := body[0].(*stmt.ExpressionStmt); ok {
+			e.outExp = s.Exp
+			return
+		}
+	}
+	e.onError(e.tok(c.Name), fmt.Sprintf("Macro '%s' does not expand to an expression.", c.Name.Text))
+	e.outExp = c
+}
+
+// VisitQuoteExpression keeps quoted code as it is written, it is only
+// expanded where a macro splices it in.
+func (e *Expander) VisitQuoteExpression(q *expression.QuoteExpression) {
+	e.outExp = q
+}
+
+// VisitUnquoteExpression splices the value an unquote had when its quote
+// was evaluated.
+func (e *Expander) VisitUnquoteExpression(u *expression.UnquoteExpression) {
+	value, ok := e.values[u]
+	if !ok {
+		e.onError(e.tok(u.Keywoard), "Can't use 'unquote' outside of a quote.")
+		e.outExp = u
+		return
+	}
+	t := e.tok(u.Keywoard)
+	switch v := value.(type) {
+	case *interpreter.Fragment:
+		if v.Exp == nil {
+			e.onError(t, "Unquoted statements can only be used as a statement.")
+			e.outExp = u
+			return
+		}
+		e.outExp = e.spliceExp(v)
+	case nil:
+		e.outExp = expression.NewLiteralExpression(token.NewToken(token.NIL, t.Line, "nil", token.NewNullValue()))
+	case bool:
+		tType := token.FALSE
+		if v {
+			tType = token.TRUE
+		}
+		e.outExp = expression.NewLiteralExpression(token.NewToken(tType, t.Line, fmt.Sprint(v), token.NewBoolValue(v)))
+	case float64:
+		e.outExp = expression.NewLiteralExpression(token.NewToken(token.NUMBER, t.Line, fmt.Sprint(v), token.NewNumValue(v)))
+	case string:
+		e.outExp = expression.NewLiteralExpression(token.NewToken(token.STRING, t.Line, fmt.Sprintf("%q", v), token.NewStringValue(v)))
+	default:
+		e.onError(t, "Only code, numbers, strings, booleans and nil can be unquoted.")
+		e.outExp = u
+	}
+}
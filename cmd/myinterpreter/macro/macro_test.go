@@ -0,0 +1,305 @@
+package macro
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/interpreter"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+)
+
+func TestExpand(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		macro unless(cond, body) {
+			if (!cond) body;
+		}
+		macro swap(a, b) {
+			var tmp = a;
+			a = b;
+			b = tmp;
+		}
+		macro square(x) { x * x; }
+		macro twice(body) { body; body; }
+		macro counter(n) {
+			fun count(i) {
+				if (i < n) show(i);
+			}
+			fun show(i) {
+				print i;
+				count(i + 1);
+			}
+			var i = 0;
+			count(i);
+		}
+		var tmp = 1;
+		var other = 2;
+		swap!(tmp, other);
+		print tmp;
+		print other;
+		unless!(tmp > 5, { print "small"; });
+		print square!(square!(1 + 1));
+		twice!({ unless!(false, { print "hi"; }); });
+		var i = "outer";
+		counter!(2);
+		print i;
+	`)
+	lex.Lex()
+	program, errs := parser.New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestExpand non nil parser error %s", errs)
+		return
+	}
+	program, errs = New().Expand(program)
+	if errs != nil {
+		t.Errorf("TestExpand non nil expand error %s", errs)
+		return
+	}
+	_, errs = interpreter.New().Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := "2\n1\nsmall\n16\nhi\nhi\n0\n1\nouter\n"
+	if res != expected || errs != nil {
+		t.Errorf("TestExpand Error, got: %s, want: %s, errors: %v", res, expected, errs)
+	}
+}
+
+func TestExpandLoopHygiene(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		macro repeat3(body) {
+			var i = 0;
+			while (i < 3) {
+				i = i + 1;
+				body;
+			}
+		}
+		macro twice(body) {
+			inner: for (var k = 0; k < 2; k = k + 1) {
+				body;
+			}
+		}
+		outer: while (true) {
+			repeat3!({ break; });
+			print "loop continues";
+			break;
+		}
+		var n = 0;
+		while (n < 2) {
+			n = n + 1;
+			repeat3!({ print n; continue; });
+			print "skipped";
+		}
+		inner: for (var j = 0; j < 3; j = j + 1) {
+			twice!({
+				if (j == 1) continue inner;
+				print j;
+			});
+		}
+		twice!({
+			var m = 0;
+			loop {
+				m = m + 1;
+				if (m > 1) break;
+				print "m";
+			}
+		});
+	`)
+	lex.Lex()
+	program, errs := parser.New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestExpandLoopHygiene non nil parser error %s", errs)
+		return
+	}
+	program, errs = New().Expand(program)
+	if errs != nil {
+		t.Errorf("TestExpandLoopHygiene non nil expand error %s", errs)
+		return
+	}
+	_, errs = interpreter.New().Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := "1\n2\n0\n0\n2\n2\nm\nm\n"
+	if res != expected || errs != nil {
+		t.Errorf("TestExpandLoopHygiene Error, got: %s, want: %s, errors: %v", res, expected, errs)
+	}
+}
+
+func TestExpandQuote(t *testing.T) {
+	// mock stdout
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	lex := lexer.New(`
+		macro unroll(n, body) {
+			var code = quote {};
+			for (var k = 0; k < n.value; k = k + 1) {
+				code = quote { unquote(code); unquote(body); };
+			}
+			return code;
+		}
+		macro twice(x) {
+			return quote(unquote(x) + unquote(x));
+		}
+		macro greet(name) {
+			var message = "hello " + name.value;
+			return quote { print unquote(message); };
+		}
+		macro shadow(e) {
+			return quote { var tmp = 10; print unquote(e) + tmp; };
+		}
+		macro pick(cond, a, b) {
+			if (cond.value) return a;
+			return quote(unquote(b));
+		}
+		var i = 0;
+		unroll!(3, { i = i + 1; print i; });
+		print twice!(21);
+		greet!("macro");
+		var tmp = 1;
+		shadow!(tmp);
+		print pick!(false, "a", "b");
+	`)
+	lex.Lex()
+	program, errs := parser.New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestExpandQuote non nil parser error %s", errs)
+		return
+	}
+	program, errs = New().Expand(program)
+	if errs != nil {
+		t.Errorf("TestExpandQuote non nil expand error %s", errs)
+		return
+	}
+	_, errs = interpreter.New().Interp(program)
+	// demock stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = rescueStdout
+	res := string(out)
+	expected := "1\n2\n3\n42\nhello macro\n11\nb\n"
+	if res != expected || errs != nil {
+		t.Errorf("TestExpandQuote Error, got: %s, want: %s, errors: %v", res, expected, errs)
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "undefined",
+			input:    "\nnope!(1);",
+			expected: []string{"[line 2] Macro error: Undefined macro 'nope'."},
+		},
+		{
+			name: "arity",
+			input: `macro m(a, b) { a + b; }
+				m!(1);`,
+			expected: []string{"[line 2] Macro error: Macro 'm' expects 2 arguments but got 1."},
+		},
+		{
+			name: "not an expression",
+			input: `macro m() { var x = 1; }
+				print m!();`,
+			expected: []string{"[line 2] Macro error: Macro 'm' does not expand to an expression."},
+		},
+		{
+			name: "block as expression",
+			input: `macro m(a) { print a; }
+				m!({ print 1; });`,
+			expected: []string{"[line 2] Macro error: Macro argument 'a' is a block and can only be used as a statement."},
+		},
+		{
+			name: "assign to argument",
+			input: `macro m(a) { a = 1; }
+				m!(2);`,
+			expected: []string{"[line 2] Macro error: Macro argument 'a' is assigned to and must be a variable."},
+		},
+		{
+			name: "recursive",
+			input: `macro m(a) { m!(a); }
+
+				m!(1);`,
+			expected: []string{"[line 3] Macro error: Macro expansion is too deep."},
+		},
+		{
+			name: "nested declaration",
+			input: `fun f() {
+					macro m() { 1; }
+				}`,
+			expected: []string{"[line 2] Macro error: Macros can only be declared at the top level."},
+		},
+		{
+			name: "redeclared",
+			input: `macro m() { 1; }
+				macro m() { 2; }`,
+			expected: []string{"[line 2] Macro error: Macro 'm' is already declared."},
+		},
+		{
+			name: "quote returns a value",
+			input: `macro m() { var q = quote(1); return 1; }
+				m!();`,
+			expected: []string{"[line 2] Macro error: Macro 'm' must return quoted code."},
+		},
+		{
+			name: "quote fails",
+			input: `macro m(x) { return quote(unquote(x.value)); }
+				m!(a);`,
+			expected: []string{"[line 2] Macro error: Macro 'm' failed: Only a literal has a value. [line 1]"},
+		},
+		{
+			name: "unquote statements as expression",
+			input: `macro m(b) { return quote(unquote(b)); }
+				print m!({ print 1; });`,
+			expected: []string{"[line 2] Macro error: Unquoted statements can only be used as a statement."},
+		},
+		{
+			name: "unquote function",
+			input: `macro m() { return quote(unquote(clock)); }
+				m!();`,
+			expected: []string{"[line 2] Macro error: Only code, numbers, strings, booleans and nil can be unquoted."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := lexer.New(tt.input)
+			lex.Lex()
+			program, errs := parser.New(lex.Tokens()).ParseProgram()
+			if errs != nil {
+				t.Errorf("TEST %s non nil parser error %v", tt.name, errs)
+				return
+			}
+			_, errs = New().Expand(program)
+			if len(errs) != len(tt.expected) {
+				t.Errorf("TEST %s Wrong amount of errors: %v, %v", tt.name, errs, tt.expected)
+				return
+			}
+			for i, err := range errs {
+				if err.Error() != tt.expected[i] {
+					t.Errorf("TEST %s Expand() got at index %v = %v, want = %v", tt.name, i, err, tt.expected[i])
+				}
+			}
+		})
+	}
+}
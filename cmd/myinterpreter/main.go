@@ -7,7 +7,9 @@ import (
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/cli"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/interpreter"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/macro"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/typecheck"
 )
 
 func main() {
@@ -22,9 +24,11 @@ func main() {
 	case cli.Parse:
 		parse(arg.FilePath)
 	case cli.Eval:
-		eval(arg.FilePath)
+		eval(arg.FilePath, arg.NoChecks)
 	case cli.Run:
-		run(arg.FilePath)
+		run(arg.FilePath, arg.NoChecks)
+	case cli.TypeCheck:
+		typeCheck(arg.FilePath)
 	}
 }
 
@@ -72,7 +76,7 @@ func parse(fileName string) {
 	}
 }
 
-func eval(fileName string) {
+func eval(fileName string, noChecks bool) {
 	fileContents, err := os.ReadFile(fileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
@@ -94,6 +98,9 @@ func eval(fileName string) {
 		os.Exit(65)
 	}
 	interp := interpreter.New()
+	if noChecks {
+		interp.DisableChecks()
+	}
 	_, errs = interp.Eval(exp)
 	if errs != nil {
 		for _, err := range errs {
@@ -105,7 +112,7 @@ func eval(fileName string) {
 	fmt.Println(interp.String())
 }
 
-func run(fileName string) {
+func run(fileName string, noChecks bool) {
 	fileContents, err := os.ReadFile(fileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
@@ -126,10 +133,61 @@ func run(fileName string) {
 		}
 		os.Exit(65)
 	}
+	exp, errs = macro.New().Expand(exp)
+	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
+		os.Exit(65)
+	}
 	interp := interpreter.New()
+	if noChecks {
+		interp.DisableChecks()
+	}
 	_, errs = interp.Interp(exp)
 	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
 		os.Exit(70)
 		return
 	}
 }
+
+func typeCheck(fileName string) {
+	fileContents, err := os.ReadFile(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(1)
+	}
+	lex := lexer.New(string(fileContents))
+	errs := lex.Lex()
+	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
+	}
+	par := parser.New(lex.Tokens())
+	program, errs := par.ParseProgram()
+	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
+		os.Exit(65)
+	}
+	program, errs = macro.New().Expand(program)
+	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
+		os.Exit(65)
+	}
+	checker := typecheck.New()
+	errs = checker.Check(program)
+	if errs != nil {
+		for _, err := range errs {
+			lexer.Report(err)
+		}
+		os.Exit(65)
+	}
+}
@@ -68,6 +68,14 @@ func (a *ASTPrinter) VisitVarStmt(s *stmt.VarStmt) {
 	a.outString = a.parenthesize("var = ", s.Init)
 }
 
+func (a *ASTPrinter) VisitUnpackStmt(s *stmt.UnpackStmt) {
+	names := make([]string, len(s.Names))
+	for i, n := range s.Names {
+		names[i] = n.Text
+	}
+	a.outString = a.parenthesize(fmt.Sprintf("var %s = ", strings.Join(names, ", ")), s.Init)
+}
+
 func (a *ASTPrinter) VisitBinary(b *expression.BinaryExpression) {
 	a.outString = a.parenthesize(b.Op.Text, b.Lhs, b.Rhs)
 }
@@ -77,7 +85,7 @@ func (a *ASTPrinter) VisitGrouping(g *expression.GroupingExpression) {
 }
 
 func (a *ASTPrinter) VisitLiteral(l *expression.LiteralExpression) {
-	if l.Val.TokenValue.Type == token.BoolValue || l.Val.TokenValue.Type == token.NullValue {
+	if l.Val.TokenValue.Type == token.BoolValue || l.Val.TokenValue.Type == token.NullValue || l.Val.TokenValue.Type == token.BytesValue {
 		a.outString = l.Val.Text
 		return
 	}
@@ -101,8 +109,64 @@ func (a *ASTPrinter) VisitLogicalExpression(u *expression.LogicalExpression) {
 }
 
 func (a *ASTPrinter) VisitWhileStmt(s *stmt.WhileStmt) {
+	header := a.parenthesize("while", s.Condition)
+	if s.Increment != nil {
+		header = a.parenthesize("while", s.Condition, s.Increment)
+	}
+	if s.Label != nil {
+		header = fmt.Sprintf("%s: %s", s.Label.Text, header)
+	}
+	s.Body.Accept(a)
+	a.outString = fmt.Sprintf("%s, {\n%s\n}", header, a.Out())
+}
+
+func (a *ASTPrinter) VisitWithStmt(s *stmt.WithStmt) {
+	header := a.parenthesize(fmt.Sprintf("with %s =", s.Name.Text), s.Init)
+	a.VisitBlockStmt(stmt.NewBlockStmt(s.Body))
+	a.outString = fmt.Sprintf("%s %s", header, a.Out())
+}
+
+func (a *ASTPrinter) VisitDoWhileStmt(s *stmt.DoWhileStmt) {
+	header := "(do)"
+	if s.Label != nil {
+		header = fmt.Sprintf("%s: %s", s.Label.Text, header)
+	}
+	s.Body.Accept(a)
+	body := a.Out()
+	a.outString = fmt.Sprintf("%s, {\n%s\n} %s", header, body, a.parenthesize("while", s.Condition))
+}
+
+func (a *ASTPrinter) VisitLoopStmt(s *stmt.LoopStmt) {
+	header := "(loop)"
+	if s.Label != nil {
+		header = fmt.Sprintf("%s: %s", s.Label.Text, header)
+	}
+	s.Body.Accept(a)
+	a.outString = fmt.Sprintf("%s, {\n%s\n}", header, a.Out())
+}
+
+func (a *ASTPrinter) VisitForInStmt(s *stmt.ForInStmt) {
+	header := a.parenthesize(fmt.Sprintf("for %s in", s.Name.Text), s.Iterable)
+	if s.Label != nil {
+		header = fmt.Sprintf("%s: %s", s.Label.Text, header)
+	}
 	s.Body.Accept(a)
-	a.outString = fmt.Sprintf("%s, {\n%s\n}", a.parenthesize("while", s.Condition), a.Out())
+	a.outString = fmt.Sprintf("%s, {\n%s\n}", header, a.Out())
+}
+
+func (a *ASTPrinter) VisitBreakStmt(s *stmt.BreakStmt) {
+	a.outString = loopControlString(s.Keywoard, s.Label)
+}
+
+func (a *ASTPrinter) VisitContinueStmt(s *stmt.ContinueStmt) {
+	a.outString = loopControlString(s.Keywoard, s.Label)
+}
+
+func loopControlString(keywoard *token.Token, label *token.Token) string {
+	if label == nil {
+		return fmt.Sprintf("(%s)", keywoard.Text)
+	}
+	return fmt.Sprintf("(%s %s)", keywoard.Text, label.Text)
 }
 
 func (a *ASTPrinter) VisitFunctionCallExpression(f *expression.FunctionCallExpression) {
@@ -112,9 +176,143 @@ func (a *ASTPrinter) VisitFunctionCallExpression(f *expression.FunctionCallExpre
 	a.outString = a.parenthesize("call", args...)
 }
 
+func (a *ASTPrinter) VisitPipelineExpression(p *expression.PipelineExpression) {
+	a.outString = a.parenthesize("|>", p.Lhs, p.Rhs)
+}
+
+func (a *ASTPrinter) VisitGetExpression(g *expression.GetExpression) {
+	name := "get"
+	if g.Optional {
+		name = "get?"
+	}
+	a.outString = a.parenthesize(fmt.Sprintf("%s %s", name, g.Name.Text), g.Object)
+}
+
+func (a *ASTPrinter) VisitIndexExpression(e *expression.IndexExpression) {
+	name := "index"
+	if e.Optional {
+		name = "index?"
+	}
+	a.outString = a.parenthesize(name, e.Object, e.Index)
+}
+
+func (a *ASTPrinter) VisitTupleExpression(e *expression.TupleExpression) {
+	a.outString = a.parenthesize("tuple", e.Elements...)
+}
+
+func (a *ASTPrinter) VisitComprehensionExpression(e *expression.ComprehensionExpression) {
+	var clauses strings.Builder
+	for _, c := range e.Clauses {
+		clauses.WriteString(" ")
+		if c.Keywoard.Type == token.IF {
+			clauses.WriteString(a.parenthesize("if", c.Condition))
+			continue
+		}
+		names := make([]string, len(c.Names))
+		for i, n := range c.Names {
+			names[i] = n.Text
+		}
+		clauses.WriteString(a.parenthesize(fmt.Sprintf("for %s in", strings.Join(names, ", ")), c.Iterable))
+	}
+	a.outString = fmt.Sprintf("(set-comp %s%s)", a.Print(e.Element), clauses.String())
+}
+
+func (a *ASTPrinter) VisitSetExpression(e *expression.SetExpression) {
+	a.outString = a.parenthesize("set", e.Elements...)
+}
+
+func (a *ASTPrinter) VisitDeferStmt(s *stmt.DeferStmt) {
+	a.outString = a.parenthesize("defer", s.Exp)
+}
+
+func (a *ASTPrinter) VisitAssertStmt(s *stmt.AssertStmt) {
+	if s.Message == nil {
+		a.outString = a.parenthesize("assert", s.Condition)
+		return
+	}
+	a.outString = a.parenthesize("assert", s.Condition, s.Message)
+}
+
+func (a *ASTPrinter) VisitSpawnStmt(s *stmt.SpawnStmt) {
+	a.outString = a.parenthesize("spawn", s.Call)
+}
+
+func (a *ASTPrinter) VisitEnumStmt(s *stmt.EnumStmt) {
+	variants := make([]string, len(s.Variants))
+	for i, v := range s.Variants {
+		variants[i] = v.Text
+	}
+	a.outString = fmt.Sprintf("enum %s (%s)", s.Name.Text, strings.Join(variants, " "))
+}
+
+func (a *ASTPrinter) VisitMacroStmt(s *stmt.MacroStmt) {
+	params := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = p.Text
+	}
+	a.VisitBlockStmt(stmt.NewBlockStmt(s.Body))
+	a.outString = fmt.Sprintf("macro %s (%s) %s", s.Name.Text, strings.Join(params, " "), a.Out())
+}
+
+func (a *ASTPrinter) VisitMacroCallExpression(e *expression.MacroCallExpression) {
+	var args strings.Builder
+	for _, arg := range e.Args {
+		args.WriteString(" ")
+		if arg.Exp != nil {
+			args.WriteString(a.Print(arg.Exp))
+			continue
+		}
+		a.VisitBlockStmt(arg.Block.(*stmt.BlockStmt))
+		args.WriteString(a.Out())
+	}
+	a.outString = fmt.Sprintf("(%s!%s)", e.Name.Text, args.String())
+}
+
+func (a *ASTPrinter) VisitQuoteExpression(e *expression.QuoteExpression) {
+	if e.Exp != nil {
+		a.outString = a.parenthesize("quote", e.Exp)
+		return
+	}
+	a.VisitBlockStmt(e.Block.(*stmt.BlockStmt))
+	a.outString = fmt.Sprintf("(quote %s)", a.Out())
+}
+
+func (a *ASTPrinter) VisitUnquoteExpression(e *expression.UnquoteExpression) {
+	a.outString = a.parenthesize("unquote", e.Exp)
+}
+
+func (a *ASTPrinter) VisitMatchExpression(m *expression.MatchExpression) {
+	var arms strings.Builder
+	for _, arm := range m.Arms {
+		arms.WriteString(" (")
+		arms.WriteString(a.printPattern(arm.Pattern))
+		if arm.Guard != nil {
+			arms.WriteString(" " + a.parenthesize("if", arm.Guard))
+		}
+		arms.WriteString(" " + a.Print(arm.Body) + ")")
+	}
+	a.outString = fmt.Sprintf("%s%s)", strings.TrimSuffix(a.parenthesize("match", m.Value), ")"), arms.String())
+}
+
+func (a *ASTPrinter) printPattern(p expression.Pattern) string {
+	switch pt := p.(type) {
+	case *expression.ValuePattern:
+		return a.Print(pt.Exp)
+	case *expression.BindingPattern:
+		return fmt.Sprintf("var %s", pt.Name.Text)
+	case *expression.WildcardPattern:
+		return "_"
+	}
+	return ""
+}
+
 func (a *ASTPrinter) VisitFunctionDeclarationStmt(f *stmt.FunctionDeclarationStmt) {
+	var decorators strings.Builder
+	for _, d := range f.Decorators {
+		decorators.WriteString(a.parenthesize("@", d) + " ")
+	}
 	a.VisitBlockStmt(stmt.NewBlockStmt(f.Body))
-	a.outString = fmt.Sprintf("fun %s () %s", f.Name.Text, a.Out())
+	a.outString = fmt.Sprintf("%sfun %s () %s", decorators.String(), f.Name.Text, a.Out())
 }
 
 // Helper function to create parenthesized expressions
@@ -138,12 +336,30 @@ type Parser struct {
 	tokens []*token.Token
 	errors []error
 	cur    int
+	// loops holds the label of every loop enclosing the statement being
+	// parsed, nil for unlabeled loops.
+	loops []*token.Token
+	// unquotes collects the unquotes of every quote enclosing the code being
+	// parsed, innermost last. quoted is set once a quote is parsed, so a
+	// macro body knows it quotes code.
+	unquotes [][]*expression.UnquoteExpression
+	quoted   bool
 }
 
 func (p *Parser) Parse() (expression.Expression, []error) {
 	return p.expression(), p.errors
 }
 
+// ParseExpression is Parse for sources that must hold a single expression
+// and nothing after it.
+func (p *Parser) ParseExpression() (expression.Expression, []error) {
+	exp := p.expression()
+	if len(p.errors) == 0 && !p.isAtEnd() {
+		p.report(NewParserError(p.peek(), "Expect end of expression."))
+	}
+	return exp, p.errors
+}
+
 func (p *Parser) ParseProgram() ([]stmt.Stmt, []error) {
 	statements := []stmt.Stmt{}
 	for !p.isAtEnd() {
@@ -153,24 +369,45 @@ func (p *Parser) ParseProgram() ([]stmt.Stmt, []error) {
 }
 
 func (p *Parser) declaration() stmt.Stmt {
+	if p.check(token.AT) {
+		return p.decoratedDeclaration()
+	}
 	if p.match(token.FUN) {
-		return p.functionDeclaration()
+		return p.functionDeclaration(nil)
 	}
 	if p.match(token.VAR) {
 		return p.varDeclaration()
 	}
+	if p.match(token.ENUM) {
+		return p.enumDeclaration()
+	}
+	if p.match(token.MACRO) {
+		return p.macroDeclaration()
+	}
 	return p.statement()
 }
 
 func (p *Parser) statement() stmt.Stmt {
+	if p.check(token.IDENTIFIER) && p.checkNext(token.COLON) {
+		return p.labeledStmt()
+	}
 	if p.match(token.IF) {
 		return p.ifStmt()
 	}
 	if p.match(token.WHILE) {
-		return p.whileStmt()
+		return p.whileStmt(nil)
 	}
 	if p.match(token.FOR) {
-		return p.forStmt()
+		return p.forStmt(nil)
+	}
+	if p.match(token.DO) {
+		return p.doWhileStmt(nil)
+	}
+	if p.match(token.LOOP) {
+		return p.loopStmt(nil)
+	}
+	if p.match(token.WITH) {
+		return p.withStmt()
 	}
 	if p.match(token.PRINT) {
 		return p.printStmt()
@@ -181,15 +418,105 @@ func (p *Parser) statement() stmt.Stmt {
 	if p.match(token.RETURN) {
 		return p.returnStmt()
 	}
+	if p.match(token.SPAWN) {
+		return p.spawnStmt()
+	}
+	if p.match(token.ASSERT) {
+		return p.assertStmt()
+	}
+	if p.match(token.DEFER) {
+		return p.deferStmt()
+	}
+	if p.match(token.BREAK) {
+		return p.breakStmt()
+	}
+	if p.match(token.CONTINUE) {
+		return p.continueStmt()
+	}
 	return p.expStmt()
 }
 
+func (p *Parser) labeledStmt() stmt.Stmt {
+	label := p.advance()
+	p.advance()
+	for _, l := range p.loops {
+		if l != nil && l.Text == label.Text {
+			p.report(NewParserError(label, "Label is already used by an enclosing loop."))
+			break
+		}
+	}
+	if p.match(token.WHILE) {
+		return p.whileStmt(label)
+	}
+	if p.match(token.FOR) {
+		return p.forStmt(label)
+	}
+	if p.match(token.DO) {
+		return p.doWhileStmt(label)
+	}
+	if p.match(token.LOOP) {
+		return p.loopStmt(label)
+	}
+	p.onError(NewParserError(p.peek(), "Expect loop after label."))
+	return nil
+}
+
+func (p *Parser) breakStmt() stmt.Stmt {
+	keywoard := p.prev()
+	label := p.loopLabel(keywoard)
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after 'break'.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewBreakStmt(keywoard, label)
+}
+
+func (p *Parser) continueStmt() stmt.Stmt {
+	keywoard := p.prev()
+	label := p.loopLabel(keywoard)
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after 'continue'.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewContinueStmt(keywoard, label)
+}
+
+// loopLabel parses the optional label after break or continue and checks
+// that the statement is inside a loop with that label.
+func (p *Parser) loopLabel(keywoard *token.Token) *token.Token {
+	var label *token.Token
+	if p.match(token.IDENTIFIER) {
+		label = p.prev()
+	}
+	if len(p.loops) == 0 {
+		p.report(NewParserError(keywoard, fmt.Sprintf("Can't use '%s' outside of a loop.", keywoard.Text)))
+		return label
+	}
+	if label == nil {
+		return nil
+	}
+	for _, l := range p.loops {
+		if l != nil && l.Text == label.Text {
+			return label
+		}
+	}
+	p.report(NewParserError(label, "Undefined loop label."))
+	return label
+}
+
 func (p *Parser) returnStmt() stmt.Stmt {
 	keywoard := p.prev()
 	var exp expression.Expression
 	if !p.check(token.SEMICOLON) {
 		exp = p.expression()
 	}
+	if p.check(token.COMMA) {
+		elements := []expression.Expression{exp}
+		for p.match(token.COMMA) {
+			elements = append(elements, p.expression())
+		}
+		exp = expression.NewTupleExpression(keywoard, elements)
+	}
 	_, err := p.consume(token.SEMICOLON, "Expect ';' after return value.")
 	if err != nil {
 		return nil
@@ -197,7 +524,48 @@ func (p *Parser) returnStmt() stmt.Stmt {
 	return stmt.NewReturnStmt(keywoard, exp)
 }
 
-func (p *Parser) whileStmt() stmt.Stmt {
+func (p *Parser) deferStmt() stmt.Stmt {
+	keywoard := p.prev()
+	exp := p.expression()
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after deferred expression.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewDeferStmt(keywoard, exp)
+}
+
+func (p *Parser) assertStmt() stmt.Stmt {
+	keywoard := p.prev()
+	start := p.cur
+	condition := p.expression()
+	source := p.sourceText(start, p.cur)
+	var message expression.Expression
+	if p.match(token.COMMA) {
+		message = p.expression()
+	}
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after assertion.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewAssertStmt(keywoard, condition, message, source)
+}
+
+func (p *Parser) spawnStmt() stmt.Stmt {
+	keywoard := p.prev()
+	exp := p.expression()
+	call, ok := exp.(*expression.FunctionCallExpression)
+	if !ok {
+		p.onError(NewParserError(keywoard, "Expect function call after 'spawn'."))
+		return nil
+	}
+	_, err := p.consume(token.SEMICOLON, "Expect ';' after spawn call.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewSpawnStmt(keywoard, call)
+}
+
+func (p *Parser) whileStmt(label *token.Token) stmt.Stmt {
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'while'.")
 	if err != nil {
 		return nil
@@ -207,11 +575,72 @@ func (p *Parser) whileStmt() stmt.Stmt {
 	if err != nil {
 		return nil
 	}
+	body := p.loopBody(label)
+	return stmt.NewWhileStmt(label, condition, body, nil)
+}
+
+func (p *Parser) withStmt() stmt.Stmt {
+	keywoard := p.prev()
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'with'.")
+	if err != nil {
+		return nil
+	}
+	name, err := p.consume(token.IDENTIFIER, "Expect resource name.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.EQUAL, "Expect '=' after resource name.")
+	if err != nil {
+		return nil
+	}
+	init := p.expression()
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after resource.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before with body.")
+	if err != nil {
+		return nil
+	}
+	body := p.blockStmt()
+	return stmt.NewWithStmt(keywoard, name, init, body)
+}
+
+func (p *Parser) doWhileStmt(label *token.Token) stmt.Stmt {
+	body := p.loopBody(label)
+	_, err := p.consume(token.WHILE, "Expect 'while' after do body.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_PAREN, "Expect '(' after 'while'.")
+	if err != nil {
+		return nil
+	}
+	condition := p.expression()
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after condition.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after do-while condition.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewDoWhileStmt(label, body, condition)
+}
+
+func (p *Parser) loopStmt(label *token.Token) stmt.Stmt {
+	body := p.loopBody(label)
+	return stmt.NewLoopStmt(label, body)
+}
+
+func (p *Parser) loopBody(label *token.Token) stmt.Stmt {
+	p.loops = append(p.loops, label)
 	body := p.statement()
-	return stmt.NewWhileStmt(condition, body)
+	p.loops = p.loops[:len(p.loops)-1]
+	return body
 }
 
-func (p *Parser) forStmt() stmt.Stmt {
+func (p *Parser) forStmt(label *token.Token) stmt.Stmt {
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil
@@ -222,7 +651,12 @@ func (p *Parser) forStmt() stmt.Stmt {
 	if p.match(token.SEMICOLON) {
 
 	} else if p.match(token.VAR) {
+		if p.check(token.IDENTIFIER) && p.checkNext(token.IN) {
+			return p.forInStmt(label)
+		}
 		initializer = p.varDeclaration()
+	} else if p.check(token.IDENTIFIER) && p.checkNext(token.IN) {
+		return p.forInStmt(label)
 	} else {
 		initializer = p.expStmt()
 	}
@@ -242,19 +676,12 @@ func (p *Parser) forStmt() stmt.Stmt {
 	if err != nil {
 		return nil
 	}
-	body := p.statement()
-
-	if incriment != nil {
-		body = stmt.NewBlockStmt([]stmt.Stmt{
-			body,
-			stmt.NewExpressionStmt(incriment),
-		})
-	}
+	body := p.loopBody(label)
 
 	if condition == nil {
 		condition = expression.NewLiteralExpression(token.NewToken(token.BoolValue, 1, "true", token.NewBoolValue(true)))
 	}
-	body = stmt.NewWhileStmt(condition, body)
+	body = stmt.NewWhileStmt(label, condition, body, incriment)
 	if initializer != nil {
 		body = stmt.NewBlockStmt([]stmt.Stmt{
 			initializer,
@@ -264,6 +691,18 @@ func (p *Parser) forStmt() stmt.Stmt {
 	return body
 }
 
+func (p *Parser) forInStmt(label *token.Token) stmt.Stmt {
+	name := p.advance()
+	p.advance()
+	iterable := p.expression()
+	_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil
+	}
+	body := p.loopBody(label)
+	return stmt.NewForInStmt(label, name, iterable, body)
+}
+
 func (p *Parser) ifStmt() stmt.Stmt {
 	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'if'.")
 	if err != nil {
@@ -282,7 +721,27 @@ func (p *Parser) ifStmt() stmt.Stmt {
 	return stmt.NewIfStmt(condition, flow, elseStmt)
 }
 
-func (p *Parser) functionDeclaration() stmt.Stmt {
+// decoratedDeclaration parses `@a @b(1) fun f() {}`, decorators are calls
+// or property accesses, like `@cache.memoize`.
+func (p *Parser) decoratedDeclaration() stmt.Stmt {
+	decorators := []expression.Expression{}
+	for p.match(token.AT) {
+		decorator := p.call()
+		if decorator == nil {
+			return nil
+		}
+		decorators = append(decorators, decorator)
+	}
+	_, err := p.consume(token.FUN, "Expect 'fun' after decorators.")
+	if err != nil {
+		return nil
+	}
+	return p.functionDeclaration(decorators)
+}
+
+func (p *Parser) functionDeclaration(decorators []expression.Expression) stmt.Stmt {
+	// the 'fun' keyword is already consumed
+	keywoard := p.prev()
 	name, err := p.consume(token.IDENTIFIER, "Expect function name.")
 	if err != nil {
 		return nil
@@ -292,6 +751,7 @@ func (p *Parser) functionDeclaration() stmt.Stmt {
 		return nil
 	}
 	args := []*token.Token{}
+	argTypes := []*stmt.TypeAnnotation{}
 	for !p.check(token.RIGHT_PAREN) {
 		arg, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
 		if err != nil {
@@ -301,7 +761,15 @@ func (p *Parser) functionDeclaration() stmt.Stmt {
 			p.onError(NewParserError(arg, "Can't have more than 255 parameters."))
 			break
 		}
+		var argType *stmt.TypeAnnotation
+		if p.match(token.COLON) {
+			argType = p.typeAnnotation()
+			if argType == nil {
+				return nil
+			}
+		}
 		args = append(args, arg)
+		argTypes = append(argTypes, argType)
 		if !p.match(token.COMMA) {
 			break
 		}
@@ -310,12 +778,195 @@ func (p *Parser) functionDeclaration() stmt.Stmt {
 	if err != nil {
 		return nil
 	}
+	var returnType *stmt.TypeAnnotation
+	if p.match(token.COLON) {
+		returnType = p.typeAnnotation()
+		if returnType == nil {
+			return nil
+		}
+	}
+	requires := []*stmt.Contract{}
+	ensures := []*stmt.Contract{}
+	for p.checkContextual("requires") || p.checkContextual("ensures") {
+		keywoard := p.advance()
+		start := p.cur
+		condition := p.expression()
+		contract := stmt.NewContract(keywoard, condition, p.sourceText(start, p.cur))
+		if keywoard.Text == "requires" {
+			requires = append(requires, contract)
+		} else {
+			ensures = append(ensures, contract)
+		}
+	}
 	_, err = p.consume(token.LEFT_BRACE, "Expect { after function body.")
 	if err != nil {
 		return nil
 	}
+	enclosingLoops := p.loops
+	p.loops = nil
+	body := p.blockStmt()
+	p.loops = enclosingLoops
+	return stmt.NewFunctionDeclarationStmt(name, body, args, argTypes, returnType, requires, ensures, decorators, keywoard, p.prev())
+}
+
+// checkContextual matches identifiers that act as keywords in one place
+// only, so they stay usable as names everywhere else.
+func (p *Parser) checkContextual(word string) bool {
+	return p.check(token.IDENTIFIER) && p.peek().Text == word
+}
+
+func (p *Parser) typeAnnotation() *stmt.TypeAnnotation {
+	var annotation *stmt.TypeAnnotation
+	if p.match(token.FUN) {
+		name := p.prev()
+		_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'fun' in type.")
+		if err != nil {
+			return nil
+		}
+		params := []*stmt.TypeAnnotation{}
+		for !p.check(token.RIGHT_PAREN) {
+			param := p.typeAnnotation()
+			if param == nil {
+				return nil
+			}
+			params = append(params, param)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+		_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after parameter types.")
+		if err != nil {
+			return nil
+		}
+		var ret *stmt.TypeAnnotation
+		if p.match(token.COLON) {
+			ret = p.typeAnnotation()
+			if ret == nil {
+				return nil
+			}
+		}
+		annotation = stmt.NewTypeAnnotation(name, params, ret, false)
+	} else if p.match(token.LEFT_PAREN) {
+		name := p.prev()
+		elements := []*stmt.TypeAnnotation{}
+		for {
+			element := p.typeAnnotation()
+			if element == nil {
+				return nil
+			}
+			elements = append(elements, element)
+			if !p.match(token.COMMA) || p.check(token.RIGHT_PAREN) {
+				break
+			}
+		}
+		_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after tuple element types.")
+		if err != nil {
+			return nil
+		}
+		annotation = stmt.NewTypeAnnotation(name, elements, nil, false)
+	} else if p.match(token.IDENTIFIER, token.NIL) {
+		name := p.prev()
+		params := []*stmt.TypeAnnotation{}
+		if p.match(token.LESS) {
+			for {
+				param := p.typeAnnotation()
+				if param == nil {
+					return nil
+				}
+				params = append(params, param)
+				if !p.match(token.COMMA) {
+					break
+				}
+			}
+			if p.check(token.GREATER_EQUAL) {
+				// `set<number>= s` lexes as `>=`, the '>' closes the type
+				// arguments and the '=' is left for the initializer
+				t := p.peek()
+				p.tokens[p.cur] = &token.Token{Type: token.EQUAL, Line: t.Line, Text: "=", TokenValue: token.NewNullValue(), Offset: t.Offset + 1, Source: t.Source}
+			} else if _, err := p.consume(token.GREATER, "Expect '>' after type arguments."); err != nil {
+				return nil
+			}
+		}
+		annotation = stmt.NewTypeAnnotation(name, params, nil, false)
+	} else {
+		p.onError(NewParserError(p.peek(), "Expect type."))
+		return nil
+	}
+	if p.match(token.QUESTION) {
+		annotation.Nullable = true
+	}
+	return annotation
+}
+
+// macroDeclaration parses `macro name(params) { template }`, the template
+// is parsed like any other block and only expanded by the macro pass.
+func (p *Parser) macroDeclaration() stmt.Stmt {
+	name, err := p.consume(token.IDENTIFIER, "Expect macro name.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_PAREN, "Expect '(' after macro name.")
+	if err != nil {
+		return nil
+	}
+	params := []*token.Token{}
+	for !p.check(token.RIGHT_PAREN) {
+		param, err := p.consume(token.IDENTIFIER, "Expect parameter name.")
+		if err != nil {
+			return nil
+		}
+		params = append(params, param)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after macro parameters.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before macro body.")
+	if err != nil {
+		return nil
+	}
+	enclosingQuoted := p.quoted
+	p.quoted = false
 	body := p.blockStmt()
-	return stmt.NewFunctionDeclarationStmt(name, body, args)
+	procedural := p.quoted
+	p.quoted = enclosingQuoted
+	return stmt.NewMacroStmt(name, params, body, procedural)
+}
+
+func (p *Parser) enumDeclaration() stmt.Stmt {
+	name, err := p.consume(token.IDENTIFIER, "Expect enum name.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' after enum name.")
+	if err != nil {
+		return nil
+	}
+	variants := []*token.Token{}
+	seen := map[string]bool{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		variant, err := p.consume(token.IDENTIFIER, "Expect variant name.")
+		if err != nil {
+			return nil
+		}
+		if seen[variant.Text] {
+			p.onError(NewParserError(variant, "Duplicate enum variant."))
+			return nil
+		}
+		seen[variant.Text] = true
+		variants = append(variants, variant)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	_, err = p.consume(token.RIGHT_BRACE, "Expect '}' after enum variants.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewEnumStmt(name, variants)
 }
 
 func (p *Parser) varDeclaration() stmt.Stmt {
@@ -323,6 +974,16 @@ func (p *Parser) varDeclaration() stmt.Stmt {
 	if err != nil {
 		return nil
 	}
+	var typ *stmt.TypeAnnotation
+	if p.match(token.COLON) {
+		typ = p.typeAnnotation()
+		if typ == nil {
+			return nil
+		}
+	}
+	if p.check(token.COMMA) {
+		return p.unpackDeclaration(name, typ)
+	}
 	var initializer expression.Expression
 
 	if p.match(token.EQUAL) {
@@ -332,7 +993,39 @@ func (p *Parser) varDeclaration() stmt.Stmt {
 	if err != nil {
 		return nil
 	}
-	return stmt.NewVarStmt(name, initializer)
+	return stmt.NewVarStmt(name, typ, initializer)
+}
+
+// unpackDeclaration parses the rest of `var a, b = tuple;` after the first
+// name and its type.
+func (p *Parser) unpackDeclaration(first *token.Token, firstType *stmt.TypeAnnotation) stmt.Stmt {
+	names := []*token.Token{first}
+	types := []*stmt.TypeAnnotation{firstType}
+	for p.match(token.COMMA) {
+		name, err := p.consume(token.IDENTIFIER, "Expect variable name.")
+		if err != nil {
+			return nil
+		}
+		var typ *stmt.TypeAnnotation
+		if p.match(token.COLON) {
+			typ = p.typeAnnotation()
+			if typ == nil {
+				return nil
+			}
+		}
+		names = append(names, name)
+		types = append(types, typ)
+	}
+	_, err := p.consume(token.EQUAL, "Expect '=' after variables to unpack.")
+	if err != nil {
+		return nil
+	}
+	initializer := p.expression()
+	_, err = p.consume(token.SEMICOLON, "Expect ';' after variable declaration.")
+	if err != nil {
+		return nil
+	}
+	return stmt.NewUnpackStmt(names, types, initializer)
 }
 
 func (p *Parser) printStmt() stmt.Stmt {
@@ -371,7 +1064,7 @@ func (p *Parser) expression() expression.Expression {
 }
 
 func (p *Parser) assignment() expression.Expression {
-	exp := p.logicalOr()
+	exp := p.pipeline()
 	if p.match(token.EQUAL) {
 		equals := p.prev()
 		value := p.assignment()
@@ -385,6 +1078,28 @@ func (p *Parser) assignment() expression.Expression {
 	return exp
 }
 
+func (p *Parser) pipeline() expression.Expression {
+	exp := p.nilCoalesce()
+
+	for p.match(token.PIPE_GREATER) {
+		op := p.prev()
+		rhs := p.nilCoalesce()
+		exp = expression.NewPipelineExpression(exp, op, rhs)
+	}
+	return exp
+}
+
+func (p *Parser) nilCoalesce() expression.Expression {
+	exp := p.logicalOr()
+
+	for p.match(token.QUESTION_QUESTION) {
+		op := p.prev()
+		rhs := p.logicalOr()
+		exp = expression.NewLogicalExpression(exp, op, rhs)
+	}
+	return exp
+}
+
 func (p *Parser) logicalOr() expression.Expression {
 	exp := p.logicalAnd()
 
@@ -421,14 +1136,45 @@ func (p *Parser) equality() expression.Expression {
 }
 
 func (p *Parser) comparison() expression.Expression {
-	exp := p.term()
+	exp := p.rangeExpression()
 
-	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS_EQUAL, token.LESS) {
+	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS_EQUAL, token.LESS, token.IN) {
 		op := p.prev()
-		rhs := p.term()
+		rhs := p.rangeExpression()
+		exp = expression.NewBinaryExpression(exp, op, rhs)
+	}
+
+	return exp
+}
+
+// rangeExpression parses `a..b` and `a..=b`, ranges do not chain.
+func (p *Parser) rangeExpression() expression.Expression {
+	exp := p.union()
+	if p.match(token.DOT_DOT, token.DOT_DOT_EQUAL) {
+		op := p.prev()
+		rhs := p.union()
+		exp = expression.NewBinaryExpression(exp, op, rhs)
+	}
+	return exp
+}
+
+func (p *Parser) union() expression.Expression {
+	exp := p.intersection()
+	for p.match(token.PIPE) {
+		op := p.prev()
+		rhs := p.intersection()
 		exp = expression.NewBinaryExpression(exp, op, rhs)
 	}
+	return exp
+}
 
+func (p *Parser) intersection() expression.Expression {
+	exp := p.term()
+	for p.match(token.AMPERSAND) {
+		op := p.prev()
+		rhs := p.term()
+		exp = expression.NewBinaryExpression(exp, op, rhs)
+	}
 	return exp
 }
 
@@ -461,40 +1207,179 @@ func (p *Parser) unary() expression.Expression {
 	return p.call()
 }
 
+// call parses a chain of calls, property accesses and indexing. Once a
+// `?.` or `?[` link is seen, the links after it are guarded: they are
+// skipped along with it when its object is nil.
 func (p *Parser) call() expression.Expression {
 	callee := p.primary()
-	for p.match(token.LEFT_PAREN) {
-		args := []expression.Expression{}
-		if !p.check(token.RIGHT_PAREN) {
-			for {
-				args = append(args, p.expression())
-				if !p.match(token.COMMA) {
-					break
-				}
+	guarded := false
+	for {
+		if p.match(token.LEFT_PAREN) {
+			callee = p.finishCall(callee, guarded)
+			if callee == nil {
+				return nil
+			}
+		} else if p.match(token.DOT, token.QUESTION_DOT) {
+			optional := p.prev().Type == token.QUESTION_DOT
+			name, err := p.consume(token.IDENTIFIER, fmt.Sprintf("Expect property name after '%s'.", p.prev().Text))
+			if err != nil {
+				return nil
+			}
+			callee = expression.NewGetExpression(callee, name, optional, guarded)
+			guarded = guarded || optional
+		} else if p.match(token.LEFT_BRACKET, token.QUESTION_BRACKET) {
+			bracket := p.prev()
+			optional := bracket.Type == token.QUESTION_BRACKET
+			index := p.expression()
+			_, err := p.consume(token.RIGHT_BRACKET, "Expect ']' after index.")
+			if err != nil {
+				return nil
+			}
+			callee = expression.NewIndexExpression(callee, bracket, index, optional, guarded)
+			guarded = guarded || optional
+		} else if v, ok := callee.(*expression.VarExpression); ok && p.check(token.BANG) && p.checkNext(token.LEFT_PAREN) {
+			p.advance()
+			p.advance()
+			callee = p.finishMacroCall(v.Name)
+			if callee == nil {
+				return nil
+			}
+		} else {
+			break
+		}
+	}
+	return callee
+}
+
+func (p *Parser) finishCall(callee expression.Expression, guarded bool) expression.Expression {
+	args := []expression.Expression{}
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			args = append(args, p.expression())
+			if !p.match(token.COMMA) {
+				break
 			}
 		}
-		if len(args) > 255 {
-			p.onError(NewParserError(p.peek(), "Can't have more than 255 arguments."))
+	}
+	if len(args) > 255 {
+		p.onError(NewParserError(p.peek(), "Can't have more than 255 arguments."))
+	}
+	rightParan, err := p.consume(token.RIGHT_PAREN, "Expect ')' after arguments.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewFunctionCallExpression(callee, args, rightParan, guarded)
+}
+
+// finishMacroCall parses the arguments of `name!(...)`, an argument is an
+// expression or a `{ block }` of statements.
+func (p *Parser) finishMacroCall(name *token.Token) expression.Expression {
+	args := []*expression.MacroArg{}
+	for !p.check(token.RIGHT_PAREN) {
+		if p.match(token.LEFT_BRACE) {
+			args = append(args, &expression.MacroArg{Block: stmt.NewBlockStmt(p.blockStmt())})
+		} else {
+			args = append(args, &expression.MacroArg{Exp: p.expression()})
+		}
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after macro arguments.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewMacroCallExpression(name, args)
+}
+
+// quoteExpression parses `quote(exp)` or `quote { statements }`, the
+// quoted code is parsed like any other code.
+func (p *Parser) quoteExpression() expression.Expression {
+	keywoard := p.prev()
+	p.quoted = true
+	p.unquotes = append(p.unquotes, []*expression.UnquoteExpression{})
+	var exp expression.Expression
+	var block any
+	if p.match(token.LEFT_BRACE) {
+		enclosingLoops := p.loops
+		p.loops = nil
+		block = stmt.NewBlockStmt(p.blockStmt())
+		p.loops = enclosingLoops
+	} else {
+		_, err := p.consume(token.LEFT_PAREN, "Expect '(' or '{' after 'quote'.")
+		if err == nil {
+			exp = p.expression()
+			_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after quoted expression.")
 		}
-		rightParan, err := p.consume(token.RIGHT_PAREN, "Expect ')' after arguments.")
 		if err != nil {
+			p.unquotes = p.unquotes[:len(p.unquotes)-1]
 			return nil
 		}
-		callee = expression.NewFunctionCallExpression(callee, args, rightParan)
 	}
-	return callee
+	unquotes := p.unquotes[len(p.unquotes)-1]
+	p.unquotes = p.unquotes[:len(p.unquotes)-1]
+	return expression.NewQuoteExpression(keywoard, exp, block, unquotes)
+}
+
+// unquoteExpression parses `unquote(exp)`. exp isn't quoted, it runs when
+// the enclosing quote is evaluated, so it is parsed outside of that quote.
+func (p *Parser) unquoteExpression() expression.Expression {
+	keywoard := p.prev()
+	if len(p.unquotes) == 0 {
+		p.report(NewParserError(keywoard, "Can't use 'unquote' outside of a quote."))
+	}
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'unquote'.")
+	if err != nil {
+		return nil
+	}
+	enclosing := p.unquotes
+	if n := len(enclosing); n > 0 {
+		// capped, so quotes in exp don't overwrite the enclosing lists
+		p.unquotes = enclosing[: n-1 : n-1]
+	}
+	exp := p.expression()
+	p.unquotes = enclosing
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after unquoted expression.")
+	if err != nil {
+		return nil
+	}
+	unquote := expression.NewUnquoteExpression(keywoard, exp)
+	if len(p.unquotes) > 0 {
+		p.unquotes[len(p.unquotes)-1] = append(p.unquotes[len(p.unquotes)-1], unquote)
+	}
+	return unquote
 }
 
 func (p *Parser) primary() expression.Expression {
-	if p.match(token.TRUE, token.FALSE, token.NIL, token.NUMBER, token.STRING) {
+	if p.match(token.TRUE, token.FALSE, token.NIL, token.NUMBER, token.STRING, token.BYTES) {
 		return expression.NewLiteralExpression(p.prev())
 	}
 	if p.match(token.IDENTIFIER) {
 		return expression.NewVarExpression(p.prev())
 	}
 
+	if p.match(token.MATCH) {
+		return p.matchExpression()
+	}
+
+	if p.match(token.HASH_BRACE) {
+		return p.setExpression()
+	}
+
+	if p.match(token.QUOTE) {
+		return p.quoteExpression()
+	}
+
+	if p.match(token.UNQUOTE) {
+		return p.unquoteExpression()
+	}
+
 	if p.match(token.LEFT_PAREN) {
+		paren := p.prev()
 		exp := p.expression()
+		if p.match(token.COMMA) {
+			return p.tupleExpression(paren, exp)
+		}
 		_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after expression.")
 		if err != nil {
 			return nil
@@ -505,6 +1390,154 @@ func (p *Parser) primary() expression.Expression {
 	return nil
 }
 
+// tupleExpression parses the rest of a tuple after its first element and
+// comma, `(a,)` is a tuple with one element.
+func (p *Parser) tupleExpression(paren *token.Token, first expression.Expression) expression.Expression {
+	elements := []expression.Expression{first}
+	for !p.check(token.RIGHT_PAREN) {
+		elements = append(elements, p.expression())
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	_, err := p.consume(token.RIGHT_PAREN, "Expect ')' after tuple elements.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewTupleExpression(paren, elements)
+}
+
+func (p *Parser) setExpression() expression.Expression {
+	brace := p.prev()
+	elements := []expression.Expression{}
+	if !p.check(token.RIGHT_BRACE) {
+		for {
+			elements = append(elements, p.expression())
+			if len(elements) == 1 && p.check(token.FOR) {
+				return p.comprehension(brace, elements[0])
+			}
+			if !p.match(token.COMMA) || p.check(token.RIGHT_BRACE) {
+				break
+			}
+		}
+	}
+	_, err := p.consume(token.RIGHT_BRACE, "Expect '}' after set elements.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewSetExpression(brace, elements)
+}
+
+// comprehension parses the clauses of a comprehension after its element,
+// the first clause is always a for.
+func (p *Parser) comprehension(brace *token.Token, element expression.Expression) expression.Expression {
+	clauses := []*expression.ComprehensionClause{}
+	for p.match(token.FOR, token.IF) {
+		keywoard := p.prev()
+		if keywoard.Type == token.IF {
+			clauses = append(clauses, expression.NewIfClause(keywoard, p.expression()))
+			continue
+		}
+		names := []*token.Token{}
+		for {
+			name, err := p.consume(token.IDENTIFIER, "Expect variable name after 'for'.")
+			if err != nil {
+				return nil
+			}
+			names = append(names, name)
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+		_, err := p.consume(token.IN, "Expect 'in' after comprehension variables.")
+		if err != nil {
+			return nil
+		}
+		clauses = append(clauses, expression.NewForClause(keywoard, names, p.expression()))
+	}
+	_, err := p.consume(token.RIGHT_BRACE, "Expect '}' after comprehension.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewComprehensionExpression(brace, element, clauses)
+}
+
+func (p *Parser) matchExpression() expression.Expression {
+	keywoard := p.prev()
+	_, err := p.consume(token.LEFT_PAREN, "Expect '(' after 'match'.")
+	if err != nil {
+		return nil
+	}
+	value := p.expression()
+	_, err = p.consume(token.RIGHT_PAREN, "Expect ')' after match value.")
+	if err != nil {
+		return nil
+	}
+	_, err = p.consume(token.LEFT_BRACE, "Expect '{' before match arms.")
+	if err != nil {
+		return nil
+	}
+	arms := []*expression.MatchArm{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		pattern := p.pattern()
+		if pattern == nil {
+			return nil
+		}
+		var guard expression.Expression
+		if p.match(token.IF) {
+			guard = p.expression()
+		}
+		_, err = p.consume(token.EQUAL_GREATER, "Expect '=>' after match pattern.")
+		if err != nil {
+			return nil
+		}
+		body := p.expression()
+		arms = append(arms, expression.NewMatchArm(pattern, guard, body))
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	_, err = p.consume(token.RIGHT_BRACE, "Expect '}' after match arms.")
+	if err != nil {
+		return nil
+	}
+	return expression.NewMatchExpression(keywoard, value, arms)
+}
+
+func (p *Parser) pattern() expression.Pattern {
+	if p.match(token.TRUE, token.FALSE, token.NIL, token.NUMBER, token.STRING) {
+		return expression.NewValuePattern(expression.NewLiteralExpression(p.prev()))
+	}
+	if p.match(token.MINUS) {
+		op := p.prev()
+		num, err := p.consume(token.NUMBER, "Expect number after '-' in pattern.")
+		if err != nil {
+			return nil
+		}
+		return expression.NewValuePattern(expression.NewUnaryExpression(op, expression.NewLiteralExpression(num)))
+	}
+	if p.match(token.IDENTIFIER) {
+		name := p.prev()
+		if p.check(token.DOT) {
+			var exp expression.Expression = expression.NewVarExpression(name)
+			for p.match(token.DOT) {
+				property, err := p.consume(token.IDENTIFIER, "Expect property name after '.'.")
+				if err != nil {
+					return nil
+				}
+				exp = expression.NewGetExpression(exp, property, false, false)
+			}
+			return expression.NewValuePattern(exp)
+		}
+		if name.Text == "_" {
+			return expression.NewWildcardPattern(name)
+		}
+		return expression.NewBindingPattern(name)
+	}
+	p.onError(NewParserError(p.peek(), "Expect pattern."))
+	return nil
+}
+
 func (p *Parser) consume(t token.TokenType, message string) (*token.Token, error) {
 	if p.check(t) {
 		return p.advance(), nil
@@ -519,6 +1552,12 @@ func (p *Parser) onError(err error) {
 	p.sync()
 }
 
+// report records an error that does not leave the parser in a broken
+// state, so there is no need to synchronize.
+func (p *Parser) report(err error) {
+	p.errors = append(p.errors, err)
+}
+
 func (p *Parser) match(tokens ...token.TokenType) bool {
 	for _, t := range tokens {
 		if p.check(t) {
@@ -539,8 +1578,14 @@ func (p *Parser) sync() {
 		switch p.peek().Type {
 		case token.CLASS:
 			return
+		case token.ENUM:
+			return
 		case token.FUN:
 			return
+		case token.AT:
+			return
+		case token.MACRO:
+			return
 		case token.VAR:
 			return
 		case token.FOR:
@@ -549,15 +1594,38 @@ func (p *Parser) sync() {
 			return
 		case token.WHILE:
 			return
+		case token.DO:
+			return
+		case token.LOOP:
+			return
+		case token.WITH:
+			return
 		case token.PRINT:
 			return
 		case token.RETURN:
 			return
+		case token.SPAWN:
+			return
+		case token.ASSERT:
+			return
+		case token.DEFER:
+			return
+		case token.BREAK:
+			return
+		case token.CONTINUE:
+			return
 		}
 		p.advance()
 	}
 }
 
+func (p *Parser) checkNext(t token.TokenType) bool {
+	if p.isAtEnd() || p.cur+1 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.cur+1].Type == t
+}
+
 func (p *Parser) check(t token.TokenType) bool {
 	if p.isAtEnd() {
 		return false
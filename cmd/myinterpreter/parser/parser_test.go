@@ -5,6 +5,7 @@ import (
 
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expression"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/stmt"
 	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
 )
 
@@ -109,3 +110,184 @@ func TestLexNilParser(t *testing.T) {
 		t.Errorf("TestParser non nil error %v", errs)
 	}
 }
+
+func TestLoopLabelErrors(t *testing.T) {
+	lex := lexer.New(`
+		break;
+		outer: while (true) {
+			while (true) { continue missing; }
+			outer: while (true) {}
+		}
+		while (true) {
+			fun f() { break; }
+		}
+	`)
+	lex.Lex()
+	_, errs := New(lex.Tokens()).ParseProgram()
+	expected := []string{
+		"2 at 'break'Can't use 'break' outside of a loop.",
+		"4 at 'missing'Undefined loop label.",
+		"5 at 'outer'Label is already used by an enclosing loop.",
+		"8 at 'break'Can't use 'break' outside of a loop.",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("TestLoopLabelErrors wrong amount of errors, got: %v, want: %v", errs, expected)
+		return
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("TestLoopLabelErrors Error, got: %s, want: %s", err.Error(), expected[i])
+		}
+	}
+}
+
+func TestTupleParser(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "(1)", expected: "(group 1.0)"},
+		{input: "(1,)", expected: "(tuple 1.0)"},
+		{input: `(b"\x00", 1)`, expected: `(tuple b"\x00" 1.0)`},
+		{input: "(1, (2, 3))", expected: "(tuple 1.0 (tuple 2.0 3.0))"},
+		{input: "#{(x, y) for x in a if x for y in b}", expected: "(set-comp (tuple var x var y) (for x in var a) (if var x) (for y in var b))"},
+	}
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		lex.Lex()
+		expression, errs := New(lex.Tokens()).Parse()
+		if errs != nil {
+			t.Errorf("TestTupleParser non nil error %v", errs)
+			continue
+		}
+		result := NewAstPrinter().Print(expression)
+		if result != tt.expected {
+			t.Errorf("TestTupleParser Error, got: %s, want: %s", result, tt.expected)
+		}
+	}
+}
+
+func TestPipelineParser(t *testing.T) {
+	lex := lexer.New("a = b |> f(1) |> g ?? h")
+	lex.Lex()
+	expression, errs := New(lex.Tokens()).Parse()
+	if errs != nil {
+		t.Errorf("TestPipelineParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().Print(expression)
+	expected := "ass (a (|> (|> var b (call var f 1.0)) (?? var g var h)))"
+	if result != expected {
+		t.Errorf("TestPipelineParser Error, got: %s, want: %s", result, expected)
+	}
+}
+
+func TestOptionalChainingParser(t *testing.T) {
+	lex := lexer.New("a?.b(1)?[0].c")
+	lex.Lex()
+	expression, errs := New(lex.Tokens()).Parse()
+	if errs != nil {
+		t.Errorf("TestOptionalChainingParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().Print(expression)
+	expected := "(get c (index? (call (get? b var a) 1.0) 0.0))"
+	if result != expected {
+		t.Errorf("TestOptionalChainingParser Error, got: %s, want: %s", result, expected)
+	}
+}
+
+func TestMacroParser(t *testing.T) {
+	lex := lexer.New("macro until(c, body) { while (!c) body; } until!(a > 1, { print a; });")
+	lex.Lex()
+	program, errs := New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestMacroParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().PrintProgram(program)
+	expected := "macro until (c body) { (while (! var c)), {\n(stmt var body)\n} }(stmt (until! (> var a 1.0) { (print var a) }))"
+	if result != expected {
+		t.Errorf("TestMacroParser Error, got: %s, want: %s", result, expected)
+	}
+}
+
+func TestQuoteParser(t *testing.T) {
+	lex := lexer.New("macro m(x) { return quote { print unquote(x); }; } macro n(x) { x; } print quote(1 + unquote(quote(unquote(2))));")
+	lex.Lex()
+	program, errs := New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestQuoteParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().PrintProgram(program)
+	expected := "macro m (x) { (return (quote { (print (unquote var x)) })) }macro n (x) { (stmt var x) }(print (quote (+ 1.0 (unquote (quote (unquote 2.0))))))"
+	if result != expected {
+		t.Errorf("TestQuoteParser Error, got: %s, want: %s", result, expected)
+	}
+	if !program[0].(*stmt.MacroStmt).Procedural || program[1].(*stmt.MacroStmt).Procedural {
+		t.Errorf("TestQuoteParser Error, only a macro that quotes code is procedural")
+	}
+	outer := program[2].(*stmt.PrintStmt).Exp.(*expression.QuoteExpression)
+	if len(outer.Unquotes) != 1 {
+		t.Errorf("TestQuoteParser Error, got %v unquotes, want: 1", len(outer.Unquotes))
+	}
+}
+
+func TestQuoteErrors(t *testing.T) {
+	lex := lexer.New(`
+		unquote(1);
+		quote(unquote(unquote(1)));
+		while (true) { quote { break; }; }
+	`)
+	lex.Lex()
+	_, errs := New(lex.Tokens()).ParseProgram()
+	expected := []string{
+		"2 at 'unquote'Can't use 'unquote' outside of a quote.",
+		"3 at 'unquote'Can't use 'unquote' outside of a quote.",
+		"4 at 'break'Can't use 'break' outside of a loop.",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("TestQuoteErrors wrong amount of errors, got: %v, want: %v", errs, expected)
+		return
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("TestQuoteErrors Error, got: %s, want: %s", err.Error(), expected[i])
+		}
+	}
+}
+
+func TestTypeArgumentsParser(t *testing.T) {
+	lex := lexer.New("var t: set<number>= #{1}; var u: set<set<number>>= #{};")
+	lex.Lex()
+	program, errs := New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestTypeArgumentsParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().PrintProgram(program)
+	expected := "(var =  (set 1.0))(var =  (set))"
+	if result != expected {
+		t.Errorf("TestTypeArgumentsParser Error, got: %s, want: %s", result, expected)
+	}
+	u := program[1].(*stmt.VarStmt).Type
+	if u.Name.Text != "set" || u.Params[0].Name.Text != "set" || u.Params[0].Params[0].Name.Text != "number" {
+		t.Errorf("TestTypeArgumentsParser Error, wrong type of u: %v", u)
+	}
+}
+
+func TestLoopParser(t *testing.T) {
+	lex := lexer.New("do print 1; while (a); l: loop { break l; }")
+	lex.Lex()
+	program, errs := New(lex.Tokens()).ParseProgram()
+	if errs != nil {
+		t.Errorf("TestLoopParser non nil error %v", errs)
+		return
+	}
+	result := NewAstPrinter().PrintProgram(program)
+	expected := "(do), {\n(print 1.0)\n} (while var a)l: (loop), {\n{ (break l) }\n}"
+	if result != expected {
+		t.Errorf("TestLoopParser Error, got: %s, want: %s", result, expected)
+	}
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+// sourceText rebuilds the source of tokens[start:end] for error messages,
+// with normalized spacing so they read the same however the code is laid
+// out. token.SourceBetween gives the text as written.
+func (p *Parser) sourceText(start int, end int) string {
+	var b strings.Builder
+	for i := start; i < end && i < len(p.tokens); i++ {
+		if i > start && needsSpace(p.tokens[start:i], p.tokens[i]) {
+			b.WriteString(" ")
+		}
+		b.WriteString(p.tokens[i].Text)
+	}
+	return b.String()
+}
+
+func needsSpace(before []*token.Token, cur *token.Token) bool {
+	prev := before[len(before)-1]
+	switch cur.Type {
+	case token.RIGHT_PAREN, token.RIGHT_BRACKET, token.COMMA, token.DOT,
+		token.DOT_DOT, token.DOT_DOT_EQUAL, token.QUESTION_DOT, token.QUESTION_BRACKET:
+		return false
+	case token.LEFT_PAREN, token.LEFT_BRACKET:
+		if isOperand(prev) {
+			return false
+		}
+	}
+	switch prev.Type {
+	case token.LEFT_PAREN, token.LEFT_BRACKET, token.DOT, token.BANG,
+		token.DOT_DOT, token.DOT_DOT_EQUAL, token.QUESTION_DOT, token.QUESTION_BRACKET:
+		return false
+	case token.MINUS:
+		return len(before) > 1 && isOperand(before[len(before)-2])
+	}
+	return true
+}
+
+func isOperand(t *token.Token) bool {
+	switch t.Type {
+	case token.IDENTIFIER, token.NUMBER, token.STRING, token.BYTES, token.RIGHT_PAREN, token.RIGHT_BRACKET,
+		token.TRUE, token.FALSE, token.NIL, token.THIS:
+		return true
+	}
+	return false
+}
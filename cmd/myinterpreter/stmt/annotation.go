@@ -0,0 +1,23 @@
+package stmt
+
+import "github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+
+// TypeAnnotation is an optional static type written in the source, it is
+// only read by the type checker and ignored at runtime.
+type TypeAnnotation struct {
+	// Name is the type name, the 'fun' keyword for function types or the
+	// '(' of tuple types, whose element types are in Params.
+	Name     *token.Token
+	Params   []*TypeAnnotation
+	Return   *TypeAnnotation
+	Nullable bool
+}
+
+func NewTypeAnnotation(name *token.Token, params []*TypeAnnotation, ret *TypeAnnotation, nullable bool) *TypeAnnotation {
+	return &TypeAnnotation{
+		Name:     name,
+		Params:   params,
+		Return:   ret,
+		Nullable: nullable,
+	}
+}
@@ -0,0 +1,28 @@
+package stmt
+
+import "github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+
+// MacroStmt is `macro name(params) { body }`. Body is the template of the
+// expansion, every parameter in it is replaced by the matching argument of
+// the call. A body that quotes code is Procedural instead: it runs at
+// expansion time with the arguments as code values and returns the code
+// the call expands to.
+type MacroStmt struct {
+	Name       *token.Token
+	Params     []*token.Token
+	Body       []Stmt
+	Procedural bool
+}
+
+func (s *MacroStmt) Accept(v Visitor) {
+	v.VisitMacroStmt(s)
+}
+
+func NewMacroStmt(name *token.Token, params []*token.Token, body []Stmt, procedural bool) *MacroStmt {
+	return &MacroStmt{
+		Name:       name,
+		Params:     params,
+		Body:       body,
+		Procedural: procedural,
+	}
+}
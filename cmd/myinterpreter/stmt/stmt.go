@@ -13,11 +13,23 @@ type Visitor interface {
 	VisitExpressionStmt(s *ExpressionStmt)
 	VisitPrintStmt(s *PrintStmt)
 	VisitVarStmt(s *VarStmt)
+	VisitUnpackStmt(s *UnpackStmt)
 	VisitBlockStmt(s *BlockStmt)
 	VisitIfStmt(s *IfStmt)
 	VisitWhileStmt(s *WhileStmt)
+	VisitForInStmt(s *ForInStmt)
+	VisitDoWhileStmt(s *DoWhileStmt)
+	VisitLoopStmt(s *LoopStmt)
+	VisitWithStmt(s *WithStmt)
 	VisitFunctionDeclarationStmt(s *FunctionDeclarationStmt)
 	VisitReturnStmt(s *ReturnStmt)
+	VisitEnumStmt(s *EnumStmt)
+	VisitSpawnStmt(s *SpawnStmt)
+	VisitAssertStmt(s *AssertStmt)
+	VisitDeferStmt(s *DeferStmt)
+	VisitBreakStmt(s *BreakStmt)
+	VisitContinueStmt(s *ContinueStmt)
+	VisitMacroStmt(s *MacroStmt)
 }
 
 type ExpressionStmt struct {
@@ -30,9 +42,18 @@ type PrintStmt struct {
 
 type VarStmt struct {
 	Name *token.Token
+	Type *TypeAnnotation
 	Init expression.Expression
 }
 
+// UnpackStmt is `var a, b = tuple;`, Types has a nil entry for every
+// name without an annotation.
+type UnpackStmt struct {
+	Names []*token.Token
+	Types []*TypeAnnotation
+	Init  expression.Expression
+}
+
 type BlockStmt struct {
 	Statements []Stmt
 }
@@ -44,14 +65,91 @@ type IfStmt struct {
 }
 
 type WhileStmt struct {
+	Label     *token.Token
 	Condition expression.Expression
 	Body      Stmt
+	// Increment is the third clause of a for loop, it also runs after
+	// continue.
+	Increment expression.Expression
+}
+
+// ForInStmt is `for (x in iterable) body`, Name is bound to a fresh
+// variable on every iteration.
+type ForInStmt struct {
+	Label    *token.Token
+	Name     *token.Token
+	Iterable expression.Expression
+	Body     Stmt
+}
+
+// DoWhileStmt is `do body while (condition);`, the body runs before the
+// condition is first checked.
+type DoWhileStmt struct {
+	Label     *token.Token
+	Body      Stmt
+	Condition expression.Expression
+}
+
+// LoopStmt is `loop body`, it only ends with break, return or an error.
+type LoopStmt struct {
+	Label *token.Token
+	Body  Stmt
+}
+
+// WithStmt is `with (name = resource) { body }`, the close method of the
+// resource is called however the body is left.
+type WithStmt struct {
+	Keywoard *token.Token
+	Name     *token.Token
+	Init     expression.Expression
+	Body     []Stmt
+}
+
+type BreakStmt struct {
+	Keywoard *token.Token
+	Label    *token.Token
+}
+
+type ContinueStmt struct {
+	Keywoard *token.Token
+	Label    *token.Token
 }
 
 type FunctionDeclarationStmt struct {
-	Name *token.Token
-	Args []*token.Token
-	Body []Stmt
+	Name       *token.Token
+	Args       []*token.Token
+	ArgTypes   []*TypeAnnotation
+	ReturnType *TypeAnnotation
+	Requires   []*Contract
+	Ensures    []*Contract
+	Body       []Stmt
+	// Decorators are applied bottom up when the function is declared, the
+	// name is bound to the result.
+	Decorators []expression.Expression
+	// Keywoard and RightBrace delimit the declaration in the source, for
+	// reflection.
+	Keywoard   *token.Token
+	RightBrace *token.Token
+}
+
+// Contract is a requires or ensures clause of a function, Source keeps the
+// condition as written for error messages.
+type Contract struct {
+	Keywoard  *token.Token
+	Condition expression.Expression
+	Source    string
+}
+
+type DeferStmt struct {
+	Keywoard *token.Token
+	Exp      expression.Expression
+}
+
+type AssertStmt struct {
+	Keywoard  *token.Token
+	Condition expression.Expression
+	Message   expression.Expression
+	Source    string
 }
 
 type ReturnStmt struct {
@@ -59,10 +157,36 @@ type ReturnStmt struct {
 	Exp expression.Expression
 }
 
+type EnumStmt struct {
+	Name     *token.Token
+	Variants []*token.Token
+}
+
+type SpawnStmt struct {
+	Keywoard *token.Token
+	Call     *expression.FunctionCallExpression
+}
+
 func (s *WhileStmt) Accept(v Visitor) {
 	v.VisitWhileStmt(s)
 }
 
+func (s *ForInStmt) Accept(v Visitor) {
+	v.VisitForInStmt(s)
+}
+
+func (s *DoWhileStmt) Accept(v Visitor) {
+	v.VisitDoWhileStmt(s)
+}
+
+func (s *LoopStmt) Accept(v Visitor) {
+	v.VisitLoopStmt(s)
+}
+
+func (s *WithStmt) Accept(v Visitor) {
+	v.VisitWithStmt(s)
+}
+
 func (s *BlockStmt) Accept(v Visitor) {
 	v.VisitBlockStmt(s)
 }
@@ -79,6 +203,10 @@ func (s *VarStmt) Accept(v Visitor) {
 	v.VisitVarStmt(s)
 }
 
+func (s *UnpackStmt) Accept(v Visitor) {
+	v.VisitUnpackStmt(s)
+}
+
 func (s *IfStmt) Accept(v Visitor) {
 	v.VisitIfStmt(s)
 }
@@ -91,6 +219,30 @@ func (s *FunctionDeclarationStmt) Accept(v Visitor) {
 	v.VisitFunctionDeclarationStmt(s)
 }
 
+func (s *EnumStmt) Accept(v Visitor) {
+	v.VisitEnumStmt(s)
+}
+
+func (s *BreakStmt) Accept(v Visitor) {
+	v.VisitBreakStmt(s)
+}
+
+func (s *ContinueStmt) Accept(v Visitor) {
+	v.VisitContinueStmt(s)
+}
+
+func (s *DeferStmt) Accept(v Visitor) {
+	v.VisitDeferStmt(s)
+}
+
+func (s *AssertStmt) Accept(v Visitor) {
+	v.VisitAssertStmt(s)
+}
+
+func (s *SpawnStmt) Accept(v Visitor) {
+	v.VisitSpawnStmt(s)
+}
+
 func NewExpressionStmt(exp expression.Expression) *ExpressionStmt {
 	return &ExpressionStmt{
 		Exp: exp,
@@ -103,13 +255,22 @@ func NewPrintStmt(exp expression.Expression) *PrintStmt {
 	}
 }
 
-func NewVarStmt(name *token.Token, init expression.Expression) *VarStmt {
+func NewVarStmt(name *token.Token, typ *TypeAnnotation, init expression.Expression) *VarStmt {
 	return &VarStmt{
 		Name: name,
+		Type: typ,
 		Init: init,
 	}
 }
 
+func NewUnpackStmt(names []*token.Token, types []*TypeAnnotation, init expression.Expression) *UnpackStmt {
+	return &UnpackStmt{
+		Names: names,
+		Types: types,
+		Init:  init,
+	}
+}
+
 func NewBlockStmt(statements []Stmt) *BlockStmt {
 	return &BlockStmt{
 		Statements: statements,
@@ -126,18 +287,93 @@ func NewIfStmt(Condition expression.Expression,
 	}
 }
 
-func NewWhileStmt(condition expression.Expression, body Stmt) *WhileStmt {
+func NewWhileStmt(label *token.Token, condition expression.Expression, body Stmt, increment expression.Expression) *WhileStmt {
 	return &WhileStmt{
+		Label:     label,
 		Condition: condition,
 		Body:      body,
+		Increment: increment,
+	}
+}
+
+func NewForInStmt(label *token.Token, name *token.Token, iterable expression.Expression, body Stmt) *ForInStmt {
+	return &ForInStmt{
+		Label:    label,
+		Name:     name,
+		Iterable: iterable,
+		Body:     body,
+	}
+}
+
+func NewDoWhileStmt(label *token.Token, body Stmt, condition expression.Expression) *DoWhileStmt {
+	return &DoWhileStmt{
+		Label:     label,
+		Body:      body,
+		Condition: condition,
 	}
 }
 
-func NewFunctionDeclarationStmt(name *token.Token, body []Stmt, args []*token.Token) *FunctionDeclarationStmt {
+func NewLoopStmt(label *token.Token, body Stmt) *LoopStmt {
+	return &LoopStmt{
+		Label: label,
+		Body:  body,
+	}
+}
+
+func NewWithStmt(keywoard *token.Token, name *token.Token, init expression.Expression, body []Stmt) *WithStmt {
+	return &WithStmt{
+		Keywoard: keywoard,
+		Name:     name,
+		Init:     init,
+		Body:     body,
+	}
+}
+
+func NewBreakStmt(keywoard *token.Token, label *token.Token) *BreakStmt {
+	return &BreakStmt{
+		Keywoard: keywoard,
+		Label:    label,
+	}
+}
+
+func NewContinueStmt(keywoard *token.Token, label *token.Token) *ContinueStmt {
+	return &ContinueStmt{
+		Keywoard: keywoard,
+		Label:    label,
+	}
+}
+
+func NewFunctionDeclarationStmt(
+	name *token.Token,
+	body []Stmt,
+	args []*token.Token,
+	argTypes []*TypeAnnotation,
+	returnType *TypeAnnotation,
+	requires []*Contract,
+	ensures []*Contract,
+	decorators []expression.Expression,
+	keywoard *token.Token,
+	rightBrace *token.Token,
+) *FunctionDeclarationStmt {
 	return &FunctionDeclarationStmt{
-		Name: name,
-		Body: body,
-		Args: args,
+		Name:       name,
+		Body:       body,
+		Args:       args,
+		ArgTypes:   argTypes,
+		ReturnType: returnType,
+		Requires:   requires,
+		Ensures:    ensures,
+		Decorators: decorators,
+		Keywoard:   keywoard,
+		RightBrace: rightBrace,
+	}
+}
+
+func NewContract(keywoard *token.Token, condition expression.Expression, source string) *Contract {
+	return &Contract{
+		Keywoard:  keywoard,
+		Condition: condition,
+		Source:    source,
 	}
 }
 
@@ -147,3 +383,33 @@ func NewReturnStmt(keywoard *token.Token, exp expression.Expression) *ReturnStmt
 		Exp: exp,
 	}
 }
+
+func NewEnumStmt(name *token.Token, variants []*token.Token) *EnumStmt {
+	return &EnumStmt{
+		Name:     name,
+		Variants: variants,
+	}
+}
+
+func NewSpawnStmt(keywoard *token.Token, call *expression.FunctionCallExpression) *SpawnStmt {
+	return &SpawnStmt{
+		Keywoard: keywoard,
+		Call:     call,
+	}
+}
+
+func NewAssertStmt(keywoard *token.Token, condition expression.Expression, message expression.Expression, source string) *AssertStmt {
+	return &AssertStmt{
+		Keywoard:  keywoard,
+		Condition: condition,
+		Message:   message,
+		Source:    source,
+	}
+}
+
+func NewDeferStmt(keywoard *token.Token, exp expression.Expression) *DeferStmt {
+	return &DeferStmt{
+		Keywoard: keywoard,
+		Exp:      exp,
+	}
+}
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"encoding/hex"
 	"fmt"
 )
 
@@ -8,50 +9,80 @@ type TokenType string
 
 const (
 	// Single-character tokens.
-	LEFT_PAREN  TokenType = "LEFT_PAREN"
-	RIGHT_PAREN TokenType = "RIGHT_PAREN"
-	LEFT_BRACE  TokenType = "LEFT_BRACE"
-	RIGHT_BRACE TokenType = "RIGHT_BRACE"
-	COMMA       TokenType = "COMMA"
-	DOT         TokenType = "DOT"
-	MINUS       TokenType = "MINUS"
-	PLUS        TokenType = "PLUS"
-	SEMICOLON   TokenType = "SEMICOLON"
-	SLASH       TokenType = "SLASH"
-	STAR        TokenType = "STAR"
+	AMPERSAND     TokenType = "AMPERSAND"
+	AT            TokenType = "AT"
+	LEFT_PAREN    TokenType = "LEFT_PAREN"
+	RIGHT_PAREN   TokenType = "RIGHT_PAREN"
+	LEFT_BRACE    TokenType = "LEFT_BRACE"
+	RIGHT_BRACE   TokenType = "RIGHT_BRACE"
+	LEFT_BRACKET  TokenType = "LEFT_BRACKET"
+	RIGHT_BRACKET TokenType = "RIGHT_BRACKET"
+	COLON         TokenType = "COLON"
+	COMMA         TokenType = "COMMA"
+	DOT           TokenType = "DOT"
+	MINUS         TokenType = "MINUS"
+	PIPE          TokenType = "PIPE"
+	PLUS          TokenType = "PLUS"
+	QUESTION      TokenType = "QUESTION"
+	SEMICOLON     TokenType = "SEMICOLON"
+	SLASH         TokenType = "SLASH"
+	STAR          TokenType = "STAR"
 
 	// One or two character tokens.
-	BANG          TokenType = "BANG"
-	BANG_EQUAL    TokenType = "BANG_EQUAL"
-	EQUAL         TokenType = "EQUAL"
-	EQUAL_EQUAL   TokenType = "EQUAL_EQUAL"
-	GREATER       TokenType = "GREATER"
-	GREATER_EQUAL TokenType = "GREATER_EQUAL"
-	LESS          TokenType = "LESS"
-	LESS_EQUAL    TokenType = "LESS_EQUAL"
+	DOT_DOT           TokenType = "DOT_DOT"
+	DOT_DOT_EQUAL     TokenType = "DOT_DOT_EQUAL"
+	BANG              TokenType = "BANG"
+	BANG_EQUAL        TokenType = "BANG_EQUAL"
+	EQUAL             TokenType = "EQUAL"
+	EQUAL_EQUAL       TokenType = "EQUAL_EQUAL"
+	EQUAL_GREATER     TokenType = "EQUAL_GREATER"
+	GREATER           TokenType = "GREATER"
+	GREATER_EQUAL     TokenType = "GREATER_EQUAL"
+	HASH_BRACE        TokenType = "HASH_BRACE"
+	PIPE_GREATER      TokenType = "PIPE_GREATER"
+	LESS              TokenType = "LESS"
+	LESS_EQUAL        TokenType = "LESS_EQUAL"
+	QUESTION_QUESTION TokenType = "QUESTION_QUESTION"
+	QUESTION_DOT      TokenType = "QUESTION_DOT"
+	QUESTION_BRACKET  TokenType = "QUESTION_BRACKET"
 
 	// Literals.
 	IDENTIFIER TokenType = "IDENTIFIER"
 	STRING     TokenType = "STRING"
+	BYTES      TokenType = "BYTES"
 	NUMBER     TokenType = "NUMBER"
 
 	// Keywords.
-	AND    TokenType = "AND"
-	CLASS  TokenType = "CLASS"
-	ELSE   TokenType = "ELSE"
-	FALSE  TokenType = "FALSE"
-	FUN    TokenType = "FUN"
-	FOR    TokenType = "FOR"
-	IF     TokenType = "IF"
-	NIL    TokenType = "NIL"
-	OR     TokenType = "OR"
-	PRINT  TokenType = "PRINT"
-	RETURN TokenType = "RETURN"
-	SUPER  TokenType = "SUPER"
-	THIS   TokenType = "THIS"
-	TRUE   TokenType = "TRUE"
-	VAR    TokenType = "VAR"
-	WHILE  TokenType = "WHILE"
+	AND      TokenType = "AND"
+	ASSERT   TokenType = "ASSERT"
+	BREAK    TokenType = "BREAK"
+	CLASS    TokenType = "CLASS"
+	CONTINUE TokenType = "CONTINUE"
+	DEFER    TokenType = "DEFER"
+	DO       TokenType = "DO"
+	ELSE     TokenType = "ELSE"
+	ENUM     TokenType = "ENUM"
+	FALSE    TokenType = "FALSE"
+	FUN      TokenType = "FUN"
+	FOR      TokenType = "FOR"
+	IF       TokenType = "IF"
+	IN       TokenType = "IN"
+	LOOP     TokenType = "LOOP"
+	MACRO    TokenType = "MACRO"
+	MATCH    TokenType = "MATCH"
+	NIL      TokenType = "NIL"
+	OR       TokenType = "OR"
+	PRINT    TokenType = "PRINT"
+	QUOTE    TokenType = "QUOTE"
+	RETURN   TokenType = "RETURN"
+	SPAWN    TokenType = "SPAWN"
+	SUPER    TokenType = "SUPER"
+	THIS     TokenType = "THIS"
+	TRUE     TokenType = "TRUE"
+	UNQUOTE  TokenType = "UNQUOTE"
+	VAR      TokenType = "VAR"
+	WHILE    TokenType = "WHILE"
+	WITH     TokenType = "WITH"
 
 	EOF TokenType = "EOF"
 )
@@ -63,6 +94,7 @@ const (
 	NumValue    = "num"
 	BoolValue   = "bool"
 	NullValue   = "null"
+	BytesValue  = "bytes"
 )
 
 type TokenValue struct {
@@ -77,6 +109,23 @@ type Token struct {
 	Line       int
 	Text       string
 	TokenValue *TokenValue
+	// Offset is where the token starts in Source, the text it was lexed
+	// from. Source is nil for tokens that later passes make up.
+	Offset int
+	Source *string
+}
+
+// SourceBetween returns the source text from the start of start to the end
+// of end as written, with its comments and formatting. It is "" when the
+// tokens weren't lexed from the same source.
+func SourceBetween(start *Token, end *Token) string {
+	if start == nil || end == nil || start.Source == nil || start.Source != end.Source {
+		return ""
+	}
+	if end.Offset+len(end.Text) < start.Offset {
+		return ""
+	}
+	return (*start.Source)[start.Offset : end.Offset+len(end.Text)]
 }
 
 func NewToken(tType TokenType, line int, text string, value *TokenValue) *Token {
@@ -109,6 +158,15 @@ func NewStringValue(str string) *TokenValue {
 	}
 }
 
+// NewBytesValue holds the decoded bytes of a bytes literal, a Go string
+// can hold any bytes.
+func NewBytesValue(b string) *TokenValue {
+	return &TokenValue{
+		Type:        BytesValue,
+		valueString: b,
+	}
+}
+
 func NewNullValue() *TokenValue {
 	return &TokenValue{
 		Type: NullValue,
@@ -119,6 +177,8 @@ func (v TokenValue) String() string {
 	switch v.Type {
 	case StringValue:
 		return v.valueString
+	case BytesValue:
+		return hex.EncodeToString([]byte(v.valueString))
 	case NullValue:
 		return "null"
 	case BoolValue:
@@ -134,7 +194,7 @@ func (v TokenValue) String() string {
 
 func (v TokenValue) GetValue() any {
 	switch v.Type {
-	case StringValue:
+	case StringValue, BytesValue:
 		return v.valueString
 	case NullValue:
 		return nil
@@ -147,22 +207,36 @@ func (v TokenValue) GetValue() any {
 }
 
 var stringToKeywoard = map[string]TokenType{
-	"and":    AND,
-	"class":  CLASS,
-	"else":   ELSE,
-	"false":  FALSE,
-	"for":    FOR,
-	"fun":    FUN,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
-	"print":  PRINT,
+	"and":      AND,
+	"assert":   ASSERT,
+	"break":    BREAK,
+	"class":    CLASS,
+	"continue": CONTINUE,
+	"defer":    DEFER,
+	"do":       DO,
+	"else":     ELSE,
+	"enum":     ENUM,
+	"false":    FALSE,
+	"for":      FOR,
+	"fun":      FUN,
+	"if":       IF,
+	"in":       IN,
+	"loop":     LOOP,
+	"macro":    MACRO,
+	"match":    MATCH,
+	"nil":      NIL,
+	"or":       OR,
+	"quote":    QUOTE,
+	"return":   RETURN,
+	"spawn":    SPAWN,
+	"super":    SUPER,
+	"this":     THIS,
+	"true":     TRUE,
+	"unquote":  UNQUOTE,
+	"var":      VAR,
+	"while":    WHILE,
+	"with":     WITH,
+	"print":    PRINT,
 }
 
 func MatchStringToKeywoard(s string) (TokenType, bool) {
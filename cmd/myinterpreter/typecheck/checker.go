@@ -0,0 +1,906 @@
+package typecheck
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/expression"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/stmt"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+type scope struct {
+	enclosing *scope
+	types     map[string]Type
+}
+
+func newScope(enclosing *scope) *scope {
+	return &scope{
+		enclosing: enclosing,
+		types:     map[string]Type{},
+	}
+}
+
+func (s *scope) define(name string, t Type) {
+	s.types[name] = t
+}
+
+// get returns the declared type of name, names the checker does not know
+// about (natives, globals declared later) are treated as any.
+func (s *scope) get(name string) Type {
+	for cur := s; cur != nil; cur = cur.enclosing {
+		if t, ok := cur.types[name]; ok {
+			return t
+		}
+	}
+	return Any
+}
+
+type Checker struct {
+	scope      *scope
+	out        Type
+	returnType Type
+	enums      map[*stmt.EnumStmt]*EnumType
+	functions  map[*stmt.FunctionDeclarationStmt]FunctionType
+	errs       []error
+}
+
+func New() *Checker {
+	return &Checker{
+		scope:     newScope(nil),
+		out:       Any,
+		enums:     map[*stmt.EnumStmt]*EnumType{},
+		functions: map[*stmt.FunctionDeclarationStmt]FunctionType{},
+	}
+}
+
+func (c *Checker) Check(program []stmt.Stmt) []error {
+	c.checkBlock(program)
+	return c.errs
+}
+
+func (c *Checker) checkBlock(stmts []stmt.Stmt) {
+	// declare enums and functions up front, so they can be referenced
+	// before their declaration like at runtime
+	for _, s := range stmts {
+		if e, ok := s.(*stmt.EnumStmt); ok {
+			c.scope.define(e.Name.Text, EnumNamespace{c.enumType(e)})
+		}
+	}
+	for _, s := range stmts {
+		if f, ok := s.(*stmt.FunctionDeclarationStmt); ok {
+			// what a decorator returns is only known once it is checked
+			if len(f.Decorators) > 0 {
+				c.scope.define(f.Name.Text, Any)
+			} else {
+				c.scope.define(f.Name.Text, c.functionType(f))
+			}
+		}
+	}
+	for _, s := range stmts {
+		c.exec(s)
+	}
+}
+
+func (c *Checker) exec(s stmt.Stmt) {
+	if s == nil {
+		return
+	}
+	s.Accept(c)
+}
+
+func (c *Checker) check(exp expression.Expression) Type {
+	if exp == nil {
+		c.out = Any
+		return c.out
+	}
+	exp.Accept(c)
+	return c.out
+}
+
+func (c *Checker) onError(t *token.Token, message string) {
+	c.errs = append(c.errs, NewTypeError(t, message))
+}
+
+func (c *Checker) enumType(s *stmt.EnumStmt) *EnumType {
+	if t, ok := c.enums[s]; ok {
+		return t
+	}
+	t := &EnumType{Name: s.Name.Text}
+	for _, v := range s.Variants {
+		t.Variants = append(t.Variants, v.Text)
+	}
+	c.enums[s] = t
+	return t
+}
+
+func (c *Checker) functionType(s *stmt.FunctionDeclarationStmt) FunctionType {
+	if t, ok := c.functions[s]; ok {
+		return t
+	}
+	t := FunctionType{
+		Params: make([]Type, len(s.Args)),
+		Return: c.resolve(s.ReturnType),
+	}
+	for i := range s.Args {
+		t.Params[i] = c.resolve(s.ArgTypes[i])
+	}
+	c.functions[s] = t
+	return t
+}
+
+// resolve turns an annotation into a type, a missing annotation is any.
+func (c *Checker) resolve(a *stmt.TypeAnnotation) Type {
+	if a == nil {
+		return Any
+	}
+	var t Type
+	if a.Name.Type == token.FUN {
+		params := make([]Type, len(a.Params))
+		for i, p := range a.Params {
+			params[i] = c.resolve(p)
+		}
+		t = FunctionType{Params: params, Return: c.resolve(a.Return)}
+	} else if a.Name.Type == token.LEFT_PAREN {
+		elems := make([]Type, len(a.Params))
+		for i, p := range a.Params {
+			elems[i] = c.resolve(p)
+		}
+		t = TupleType{elems}
+	} else {
+		t = c.resolveNamed(a)
+	}
+	if a.Nullable {
+		return nullable(t)
+	}
+	return t
+}
+
+func (c *Checker) resolveNamed(a *stmt.TypeAnnotation) Type {
+	if a.Name.Text == "list" || a.Name.Text == "set" {
+		if len(a.Params) != 1 {
+			c.onError(a.Name, fmt.Sprintf("Type '%s' expects one type argument.", a.Name.Text))
+			return Any
+		}
+		if a.Name.Text == "set" {
+			return SetType{c.resolve(a.Params[0])}
+		}
+		return ListType{c.resolve(a.Params[0])}
+	}
+	if len(a.Params) != 0 {
+		c.onError(a.Name, fmt.Sprintf("Type '%s' does not take type arguments.", a.Name.Text))
+		return Any
+	}
+	switch a.Name.Text {
+	case "any":
+		return Any
+	case "number":
+		return Number
+	case "string":
+		return String
+	case "bool":
+		return Bool
+	case "nil":
+		return Nil
+	case "range":
+		return Range
+	case "bytes":
+		return Bytes
+	}
+	if namespace, ok := c.scope.get(a.Name.Text).(EnumNamespace); ok {
+		return namespace.Enum
+	}
+	c.onError(a.Name, fmt.Sprintf("Unknown type '%s'.", a.Name.Text))
+	return Any
+}
+
+func (c *Checker) VisitExpressionStmt(s *stmt.ExpressionStmt) {
+	c.check(s.Exp)
+}
+
+func (c *Checker) VisitPrintStmt(s *stmt.PrintStmt) {
+	c.check(s.Exp)
+}
+
+func (c *Checker) VisitVarStmt(s *stmt.VarStmt) {
+	t := Any
+	if s.Init != nil {
+		t = c.check(s.Init)
+	}
+	if s.Type == nil {
+		if t == Nil {
+			t = Any
+		}
+		c.scope.define(s.Name.Text, t)
+		return
+	}
+	declared := c.resolve(s.Type)
+	if s.Init == nil {
+		t = Nil
+	}
+	if !isAssignable(t, declared) {
+		c.onError(s.Name, fmt.Sprintf("Cannot assign %s to '%s' of type %s.", t, s.Name.Text, declared))
+	}
+	c.scope.define(s.Name.Text, declared)
+}
+
+func (c *Checker) VisitUnpackStmt(s *stmt.UnpackStmt) {
+	elems := c.unpackTypes(s.Names, c.check(s.Init))
+	for i, name := range s.Names {
+		if s.Types[i] == nil {
+			c.scope.define(name.Text, elems[i])
+			continue
+		}
+		declared := c.resolve(s.Types[i])
+		if !isAssignable(elems[i], declared) {
+			c.onError(name, fmt.Sprintf("Cannot assign %s to '%s' of type %s.", elems[i], name.Text, declared))
+		}
+		c.scope.define(name.Text, declared)
+	}
+}
+
+// unpackTypes returns the types of names when they are bound to a value of
+// type t, several names unpack a tuple.
+func (c *Checker) unpackTypes(names []*token.Token, t Type) []Type {
+	if len(names) == 1 {
+		return []Type{t}
+	}
+	elems := make([]Type, len(names))
+	for i := range elems {
+		elems[i] = Any
+	}
+	switch tuple := t.(type) {
+	case TupleType:
+		if len(tuple.Elems) != len(names) {
+			c.onError(names[0], fmt.Sprintf("Cannot unpack %s into %v variables.", t, len(names)))
+		} else {
+			elems = tuple.Elems
+		}
+	default:
+		if t != Any {
+			c.onError(names[0], fmt.Sprintf("Cannot unpack %s, expected a tuple.", t))
+		}
+	}
+	return elems
+}
+
+func (c *Checker) VisitBlockStmt(s *stmt.BlockStmt) {
+	prev := c.scope
+	c.scope = newScope(prev)
+	c.checkBlock(s.Statements)
+	c.scope = prev
+}
+
+func (c *Checker) VisitIfStmt(s *stmt.IfStmt) {
+	c.check(s.Condition)
+	c.exec(s.ThenBranch)
+	c.exec(s.ElseBranch)
+}
+
+func (c *Checker) VisitWhileStmt(s *stmt.WhileStmt) {
+	c.check(s.Condition)
+	c.exec(s.Body)
+	if s.Increment != nil {
+		c.check(s.Increment)
+	}
+}
+
+func (c *Checker) VisitWithStmt(s *stmt.WithStmt) {
+	resource := c.check(s.Init)
+	prev := c.scope
+	c.scope = newScope(prev)
+	c.scope.define(s.Name.Text, resource)
+	c.checkBlock(s.Body)
+	c.scope = prev
+}
+
+func (c *Checker) VisitDoWhileStmt(s *stmt.DoWhileStmt) {
+	c.exec(s.Body)
+	c.check(s.Condition)
+}
+
+func (c *Checker) VisitLoopStmt(s *stmt.LoopStmt) {
+	c.exec(s.Body)
+}
+
+func (c *Checker) VisitForInStmt(s *stmt.ForInStmt) {
+	iterable := c.check(s.Iterable)
+	elem, ok := elementType(iterable)
+	if !ok {
+		c.onError(s.Name, fmt.Sprintf("Can only iterate over ranges, strings, bytes, enums, sets and tuples, got %s.", iterable))
+		elem = Any
+	}
+	prev := c.scope
+	c.scope = newScope(prev)
+	c.scope.define(s.Name.Text, elem)
+	c.exec(s.Body)
+	c.scope = prev
+}
+
+func (c *Checker) VisitBreakStmt(s *stmt.BreakStmt) {}
+
+func (c *Checker) VisitContinueStmt(s *stmt.ContinueStmt) {}
+
+func (c *Checker) VisitFunctionDeclarationStmt(s *stmt.FunctionDeclarationStmt) {
+	fnType := c.functionType(s)
+	c.scope.define(s.Name.Text, c.decorate(s, fnType))
+
+	prevScope, prevReturn := c.scope, c.returnType
+	c.scope = newScope(prevScope)
+	c.returnType = fnType.Return
+	for i, arg := range s.Args {
+		c.scope.define(arg.Text, fnType.Params[i])
+	}
+	for _, contract := range s.Requires {
+		c.check(contract.Condition)
+	}
+	if len(s.Ensures) > 0 {
+		c.scope = newScope(c.scope)
+		c.scope.define("result", fnType.Return)
+		for _, contract := range s.Ensures {
+			c.check(contract.Condition)
+		}
+		c.scope = c.scope.enclosing
+	}
+	c.checkBlock(s.Body)
+	if s.ReturnType != nil && !isAssignable(Nil, fnType.Return) && !definitelyReturns(s.Body) {
+		c.onError(s.Name, fmt.Sprintf("Function '%s' must return a value of type %s.", s.Name.Text, fnType.Return))
+	}
+	c.scope, c.returnType = prevScope, prevReturn
+}
+
+// decorate returns the type the name of a function declaration is bound
+// to once its decorators have been applied.
+func (c *Checker) decorate(s *stmt.FunctionDeclarationStmt, fnType FunctionType) Type {
+	decorators := make([]Type, len(s.Decorators))
+	for i, d := range s.Decorators {
+		decorators[i] = c.check(d)
+	}
+	var t Type = fnType
+	for i := len(decorators) - 1; i >= 0; i-- {
+		switch d := decorators[i].(type) {
+		case FunctionType:
+			if len(d.Params) != 1 {
+				c.onError(s.Name, fmt.Sprintf("Decorators must take 1 argument, got %s.", d))
+				return Any
+			}
+			if !isAssignable(t, d.Params[0]) {
+				c.onError(s.Name, fmt.Sprintf("Cannot decorate %s with %s.", t, d))
+			}
+			t = d.Return
+		default:
+			if d != Any {
+				c.onError(s.Name, fmt.Sprintf("Decorators must be functions, got %s.", d))
+			}
+			return Any
+		}
+	}
+	return t
+}
+
+func (c *Checker) VisitReturnStmt(s *stmt.ReturnStmt) {
+	t := Nil
+	if s.Exp != nil {
+		t = c.check(s.Exp)
+	}
+	if c.returnType == nil {
+		return
+	}
+	if !isAssignable(t, c.returnType) {
+		c.onError(s.Keywoard, fmt.Sprintf("Cannot return %s from a function returning %s.", t, c.returnType))
+	}
+}
+
+func (c *Checker) VisitMacroStmt(s *stmt.MacroStmt) {}
+
+// VisitMacroCallExpression is only reached for code that skipped the macro
+// pass, the expansion is unknown.
+func (c *Checker) VisitMacroCallExpression(e *expression.MacroCallExpression) {
+	c.out = Any
+}
+
+// Quoted code runs only where it is spliced in, so it isn't checked here.
+func (c *Checker) VisitQuoteExpression(e *expression.QuoteExpression) {
+	c.out = Any
+}
+
+func (c *Checker) VisitUnquoteExpression(e *expression.UnquoteExpression) {
+	c.out = Any
+}
+
+func (c *Checker) VisitEnumStmt(s *stmt.EnumStmt) {
+	c.scope.define(s.Name.Text, EnumNamespace{c.enumType(s)})
+}
+
+func (c *Checker) VisitDeferStmt(s *stmt.DeferStmt) {
+	c.check(s.Exp)
+}
+
+func (c *Checker) VisitAssertStmt(s *stmt.AssertStmt) {
+	c.check(s.Condition)
+	if s.Message != nil {
+		c.check(s.Message)
+	}
+}
+
+func (c *Checker) VisitSpawnStmt(s *stmt.SpawnStmt) {
+	c.check(s.Call)
+}
+
+func (c *Checker) VisitBinary(b *expression.BinaryExpression) {
+	lhs := c.check(b.Lhs)
+	rhs := c.check(b.Rhs)
+	isNumeric := isAssignable(lhs, Number) && isAssignable(rhs, Number)
+	switch b.Op.Type {
+	case token.PLUS:
+		switch {
+		case lhs == Number && rhs == Number:
+			c.out = Number
+		case lhs == String && rhs == String:
+			c.out = String
+		case lhs == Bytes && rhs == Bytes:
+			c.out = Bytes
+		case (lhs == Any || lhs == Number || lhs == String || lhs == Bytes) && (rhs == Any || rhs == Number || rhs == String || rhs == Bytes) && (lhs == Any || rhs == Any):
+			c.out = Any
+		default:
+			c.onError(b.Op, fmt.Sprintf("Operands of '+' must be two numbers or two strings, got %s and %s.", lhs, rhs))
+			c.out = Any
+		}
+	case token.MINUS, token.PIPE, token.AMPERSAND:
+		if set, ok := c.setOperands(lhs, rhs); ok {
+			c.out = set
+			return
+		}
+		if b.Op.Type != token.MINUS {
+			c.onError(b.Op, fmt.Sprintf("Operands of '%s' must be sets, got %s and %s.", b.Op.Text, lhs, rhs))
+			c.out = Any
+			return
+		}
+		if !isNumeric {
+			c.onError(b.Op, fmt.Sprintf("Operands of '%s' must be numbers, got %s and %s.", b.Op.Text, lhs, rhs))
+		}
+		c.out = Number
+		if lhs == Any && rhs == Any {
+			// could be a set difference just as well
+			c.out = Any
+		}
+	case token.SLASH, token.STAR:
+		if !isNumeric {
+			c.onError(b.Op, fmt.Sprintf("Operands of '%s' must be numbers, got %s and %s.", b.Op.Text, lhs, rhs))
+		}
+		c.out = Number
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		if !isNumeric {
+			c.onError(b.Op, fmt.Sprintf("Operands of '%s' must be numbers, got %s and %s.", b.Op.Text, lhs, rhs))
+		}
+		c.out = Bool
+	case token.DOT_DOT, token.DOT_DOT_EQUAL:
+		if !isNumeric {
+			c.onError(b.Op, fmt.Sprintf("Range bounds must be numbers, got %s and %s.", lhs, rhs))
+		}
+		c.out = Range
+	case token.IN:
+		if _, ok := elementType(rhs); !ok {
+			c.onError(b.Op, fmt.Sprintf("Right operand of 'in' must be a range, string, bytes, enum, set or tuple, got %s.", rhs))
+		} else if rhs == String && !isAssignable(lhs, String) {
+			c.onError(b.Op, fmt.Sprintf("Left operand of 'in' must be a string when searching a string, got %s.", lhs))
+		}
+		c.out = Bool
+	case token.EQUAL_EQUAL, token.BANG_EQUAL:
+		c.out = Bool
+	default:
+		c.out = Any
+	}
+}
+
+// setOperands returns the result type of a set operation on lhs and rhs,
+// it fails unless one side is a set and the other could be one.
+func (c *Checker) setOperands(lhs Type, rhs Type) (Type, bool) {
+	l, lOk := lhs.(SetType)
+	r, rOk := rhs.(SetType)
+	switch {
+	case lOk && rOk:
+		return SetType{join(l.Elem, r.Elem)}, true
+	case lOk && rhs == Any:
+		return l, true
+	case rOk && lhs == Any:
+		return r, true
+	}
+	return nil, false
+}
+
+func (c *Checker) VisitGrouping(g *expression.GroupingExpression) {
+	c.check(g.Exp)
+}
+
+func (c *Checker) VisitUnary(u *expression.UnaryExpression) {
+	t := c.check(u.Rhs)
+	switch u.Op.Type {
+	case token.MINUS:
+		if !isAssignable(t, Number) {
+			c.onError(u.Op, fmt.Sprintf("Operand of '-' must be a number, got %s.", t))
+		}
+		c.out = Number
+	case token.BANG:
+		c.out = Bool
+	default:
+		c.out = Any
+	}
+}
+
+func (c *Checker) VisitLiteral(l *expression.LiteralExpression) {
+	switch l.Val.TokenValue.Type {
+	case token.NumValue:
+		c.out = Number
+	case token.StringValue:
+		c.out = String
+	case token.BytesValue:
+		c.out = Bytes
+	case token.BoolValue:
+		c.out = Bool
+	default:
+		c.out = Nil
+	}
+}
+
+func (c *Checker) VisitVarExpression(v *expression.VarExpression) {
+	c.out = c.scope.get(v.Name.Text)
+}
+
+func (c *Checker) VisitAssignmentExpression(a *expression.AssignmentExpression) {
+	t := c.check(a.Val)
+	declared := c.scope.get(a.Name.Text)
+	if !isAssignable(t, declared) {
+		c.onError(a.Name, fmt.Sprintf("Cannot assign %s to '%s' of type %s.", t, a.Name.Text, declared))
+	}
+	c.out = t
+}
+
+func (c *Checker) VisitLogicalExpression(l *expression.LogicalExpression) {
+	lhs := c.check(l.Lhs)
+	rhs := c.check(l.Rhs)
+	if l.Op.Type != token.QUESTION_QUESTION {
+		c.out = join(lhs, rhs)
+		return
+	}
+	switch t := lhs.(type) {
+	case Nullable:
+		c.out = join(t.Inner, rhs)
+	default:
+		if lhs == Nil {
+			c.out = rhs
+		} else {
+			c.out = lhs
+		}
+	}
+}
+
+func (c *Checker) VisitFunctionCallExpression(f *expression.FunctionCallExpression) {
+	callee, ok := chainObject(c.check(f.Callee), false, f.Guarded)
+	args := make([]Type, len(f.Args))
+	for i, a := range f.Args {
+		args[i] = c.check(a)
+	}
+	if !ok {
+		c.out = Nil
+		return
+	}
+	c.out = c.call(f.RightParan, callee, args)
+	if f.Guarded {
+		c.out = nullable(c.out)
+	}
+}
+
+// chainObject returns the type a link of a call chain is checked against.
+// Optional and guarded links only run when the object is not nil, so a
+// nullable object is unwrapped, ok is false when it is always nil.
+func chainObject(object Type, optional bool, guarded bool) (Type, bool) {
+	if !optional && !guarded {
+		return object, true
+	}
+	if object == Nil {
+		return Nil, false
+	}
+	if n, ok := object.(Nullable); ok {
+		return n.Inner, true
+	}
+	return object, true
+}
+
+func (c *Checker) VisitPipelineExpression(p *expression.PipelineExpression) {
+	args := []Type{c.check(p.Lhs)}
+	var callee Type
+	if call, ok := p.Rhs.(*expression.FunctionCallExpression); ok {
+		callee = c.check(call.Callee)
+		for _, a := range call.Args {
+			args = append(args, c.check(a))
+		}
+	} else {
+		callee = c.check(p.Rhs)
+	}
+	c.out = c.call(p.Op, callee, args)
+}
+
+// call checks a call of callee with args and returns its result type, t is
+// the token errors are reported at.
+func (c *Checker) call(t *token.Token, callee Type, args []Type) Type {
+	if callee == Any {
+		return Any
+	}
+	fn, ok := callee.(FunctionType)
+	if !ok {
+		c.onError(t, fmt.Sprintf("Can only call functions and classes, got %s.", callee))
+		return Any
+	}
+	if len(fn.Params) != len(args) {
+		c.onError(t, fmt.Sprintf("Expected %v arguments but got %v.", len(fn.Params), len(args)))
+	} else {
+		for i, a := range args {
+			if !isAssignable(a, fn.Params[i]) {
+				c.onError(t, fmt.Sprintf("Argument %v of type %s is not assignable to parameter of type %s.", i+1, a, fn.Params[i]))
+			}
+		}
+	}
+	return fn.Return
+}
+
+func (c *Checker) VisitGetExpression(g *expression.GetExpression) {
+	object, ok := chainObject(c.check(g.Object), g.Optional, g.Guarded)
+	if !ok {
+		c.out = Nil
+		return
+	}
+	c.get(g, object)
+	if g.Optional || g.Guarded {
+		c.out = nullable(c.out)
+	}
+}
+
+func (c *Checker) get(g *expression.GetExpression, object Type) {
+	switch t := object.(type) {
+	case EnumNamespace:
+		if !t.Enum.hasVariant(g.Name.Text) {
+			c.onError(g.Name, fmt.Sprintf("Undefined variant '%s' of enum %s.", g.Name.Text, t.Enum.Name))
+			c.out = Any
+			return
+		}
+		c.out = t.Enum
+	case SetType:
+		method, ok := t.methods()[g.Name.Text]
+		if !ok {
+			c.onError(g.Name, fmt.Sprintf("Undefined property '%s' of %s.", g.Name.Text, object))
+			c.out = Any
+			return
+		}
+		c.out = method
+	case *EnumType:
+		switch g.Name.Text {
+		case "name":
+			c.out = String
+		case "ordinal":
+			c.out = Number
+		default:
+			c.onError(g.Name, fmt.Sprintf("Undefined property '%s'.", g.Name.Text))
+			c.out = Any
+		}
+	default:
+		// only primitives have method tables, and other types like
+		// FunctionType can't be used as map keys
+		if p, ok := object.(primitive); ok && builtinMethods[p] != nil {
+			method, ok := builtinMethods[p][g.Name.Text]
+			if !ok {
+				c.onError(g.Name, fmt.Sprintf("Undefined property '%s' of %s.", g.Name.Text, object))
+				c.out = Any
+				return
+			}
+			c.out = method
+			return
+		}
+		if object != Any {
+			c.onError(g.Name, fmt.Sprintf("Value of type %s has no properties.", object))
+		}
+		c.out = Any
+	}
+}
+
+func (c *Checker) VisitIndexExpression(e *expression.IndexExpression) {
+	object, ok := chainObject(c.check(e.Object), e.Optional, e.Guarded)
+	index := c.check(e.Index)
+	if !ok {
+		c.out = Nil
+		return
+	}
+	c.index(e, object, index)
+	if e.Optional || e.Guarded {
+		c.out = nullable(c.out)
+	}
+}
+
+func (c *Checker) index(e *expression.IndexExpression, object Type, index Type) {
+	kind := "String"
+	c.out = String
+	switch t := object.(type) {
+	case TupleType:
+		kind = "Tuple"
+		c.out = c.tupleIndex(t, e.Index, index)
+	default:
+		if object == Bytes {
+			kind = "Bytes"
+			c.out = bytesIndex(index)
+			break
+		}
+		if object == Any {
+			c.out = Any
+		} else if object != String {
+			c.onError(e.Bracket, fmt.Sprintf("Only strings, bytes and tuples can be indexed, got %s.", object))
+		}
+	}
+	if !isAssignable(index, Number) && !isAssignable(index, Range) {
+		c.onError(e.Bracket, fmt.Sprintf("%s index must be a number or a range, got %s.", kind, index))
+	}
+}
+
+// bytesIndex returns the type of b[index], a number for a single byte and
+// bytes for a slice.
+func bytesIndex(index Type) Type {
+	switch index {
+	case Number:
+		return Number
+	case Range:
+		return Bytes
+	}
+	return Any
+}
+
+// tupleIndex returns the type of t[index], constant indices pick out the
+// exact element type.
+func (c *Checker) tupleIndex(t TupleType, exp expression.Expression, index Type) Type {
+	if index != Number {
+		return Any
+	}
+	literal, ok := exp.(*expression.LiteralExpression)
+	if !ok {
+		return t.elem()
+	}
+	n := literal.Val.TokenValue.GetValue().(float64)
+	if n < 0 || int(n) >= len(t.Elems) || n != float64(int(n)) {
+		c.onError(literal.Val, fmt.Sprintf("Tuple index %v is out of bounds for %s.", n, t))
+		return Any
+	}
+	return t.Elems[int(n)]
+}
+
+func (c *Checker) VisitTupleExpression(e *expression.TupleExpression) {
+	elems := make([]Type, len(e.Elements))
+	for i, el := range e.Elements {
+		elems[i] = c.check(el)
+	}
+	c.out = TupleType{elems}
+}
+
+func (c *Checker) VisitComprehensionExpression(e *expression.ComprehensionExpression) {
+	prev := c.scope
+	for _, clause := range e.Clauses {
+		if clause.Keywoard.Type == token.IF {
+			c.check(clause.Condition)
+			continue
+		}
+		iterable := c.check(clause.Iterable)
+		elem, ok := elementType(iterable)
+		if !ok {
+			c.onError(clause.Keywoard, fmt.Sprintf("Can only iterate over ranges, strings, bytes, enums, sets and tuples, got %s.", iterable))
+			elem = Any
+		}
+		c.scope = newScope(c.scope)
+		for i, t := range c.unpackTypes(clause.Names, elem) {
+			c.scope.define(clause.Names[i].Text, t)
+		}
+	}
+	elem := c.check(e.Element)
+	c.scope = prev
+	c.out = SetType{elem}
+}
+
+func (c *Checker) VisitSetExpression(e *expression.SetExpression) {
+	var elem Type
+	for _, el := range e.Elements {
+		t := c.check(el)
+		if elem == nil {
+			elem = t
+		} else {
+			elem = join(elem, t)
+		}
+	}
+	if elem == nil {
+		elem = Any
+	}
+	c.out = SetType{elem}
+}
+
+func (c *Checker) VisitMatchExpression(m *expression.MatchExpression) {
+	value := c.check(m.Value)
+	var result Type
+	for _, arm := range m.Arms {
+		prev := c.scope
+		c.scope = newScope(prev)
+		switch p := arm.Pattern.(type) {
+		case *expression.ValuePattern:
+			c.check(p.Exp)
+		case *expression.BindingPattern:
+			c.scope.define(p.Name.Text, value)
+		}
+		if arm.Guard != nil {
+			c.check(arm.Guard)
+		}
+		body := c.check(arm.Body)
+		if result == nil {
+			result = body
+		} else {
+			result = join(result, body)
+		}
+		c.scope = prev
+	}
+	if result == nil {
+		result = Any
+	}
+	c.out = result
+}
+
+// definitelyReturns reports whether every path through stmts ends in a
+// return statement.
+func definitelyReturns(stmts []stmt.Stmt) bool {
+	for _, s := range stmts {
+		switch st := s.(type) {
+		case *stmt.ReturnStmt:
+			return true
+		case *stmt.BlockStmt:
+			if definitelyReturns(st.Statements) {
+				return true
+			}
+		case *stmt.IfStmt:
+			if st.ElseBranch != nil &&
+				definitelyReturns([]stmt.Stmt{st.ThenBranch}) &&
+				definitelyReturns([]stmt.Stmt{st.ElseBranch}) {
+				return true
+			}
+		case *stmt.DoWhileStmt:
+			if definitelyReturns([]stmt.Stmt{st.Body}) {
+				return true
+			}
+		case *stmt.LoopStmt:
+			// a loop without a break is only left by returning
+			if !breaksOut(st.Body, st.Label, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// breaksOut reports whether s holds a break leaving the loop labeled
+// label, nested is set inside inner loops where only labeled breaks do.
+func breaksOut(s stmt.Stmt, label *token.Token, nested bool) bool {
+	switch st := s.(type) {
+	case *stmt.BreakStmt:
+		if st.Label == nil {
+			return !nested
+		}
+		return label != nil && st.Label.Text == label.Text
+	case *stmt.BlockStmt:
+		for _, inner := range st.Statements {
+			if breaksOut(inner, label, nested) {
+				return true
+			}
+		}
+	case *stmt.IfStmt:
+		return breaksOut(st.ThenBranch, label, nested) || breaksOut(st.ElseBranch, label, nested)
+	case *stmt.WhileStmt:
+		return breaksOut(st.Body, label, true)
+	case *stmt.ForInStmt:
+		return breaksOut(st.Body, label, true)
+	case *stmt.DoWhileStmt:
+		return breaksOut(st.Body, label, true)
+	case *stmt.LoopStmt:
+		return breaksOut(st.Body, label, true)
+	}
+	return false
+}
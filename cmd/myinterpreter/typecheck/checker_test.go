@@ -0,0 +1,282 @@
+package typecheck
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/parser"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "well typed",
+			input: `
+				enum Color { Red, Green }
+				var x: number = 1;
+				var name: string? = nil;
+				var label: string = name ?? "default";
+				var c: Color = Color.Red;
+				var xs: list<number>? = nil;
+				fun add(a: number, b: number): number {
+					return a + b;
+				}
+				fun apply(f: fun(number, number): number, v: number): number {
+					return f(v, v);
+				}
+				var untyped = clock;
+				x = apply(add, x) + untyped();
+			`,
+			expected: []string{},
+		},
+		{
+			name: "mismatches",
+			input: `
+				var bad: number = "str";
+				fun add(a: number, b: number): number {
+					return a + b;
+				}
+				add(1, "2");
+				var s = "a" - 1;
+				s = "b";
+				var n = 1;
+				n = "one";
+			`,
+			expected: []string{
+				"[line 2] Type error: Cannot assign string to 'bad' of type number.",
+				"[line 6] Type error: Argument 2 of type string is not assignable to parameter of type number.",
+				"[line 7] Type error: Operands of '-' must be numbers, got string and number.",
+				"[line 8] Type error: Cannot assign string to 's' of type number.",
+				"[line 10] Type error: Cannot assign string to 'n' of type number.",
+			},
+		},
+		{
+			name: "functions",
+			input: `
+				fun noReturn(a: number): string {
+					if (a > 1) return "x";
+				}
+				fun wrongReturn(): number {
+					return "x";
+				}
+				fun nullable(a: bool): number? {
+					if (a) return 1;
+				}
+				noReturn();
+				var v: Unknown = nil;
+			`,
+			expected: []string{
+				"[line 2] Type error: Function 'noReturn' must return a value of type string.",
+				"[line 6] Type error: Cannot return string from a function returning number.",
+				"[line 11] Type error: Expected 1 arguments but got 0.",
+				"[line 12] Type error: Unknown type 'Unknown'.",
+			},
+		},
+		{
+			name: "builtin methods",
+			input: `
+				var n: number = "abc".length() + 2.5.floor();
+				var bad: number = "x".trim();
+				"x".nope();
+				"x".contains(1);
+			`,
+			expected: []string{
+				"[line 3] Type error: Cannot assign string to 'bad' of type number.",
+				"[line 4] Type error: Undefined property 'nope' of string.",
+				"[line 5] Type error: Argument 1 of type number is not assignable to parameter of type string.",
+			},
+		},
+		{
+			name: "ranges",
+			input: `
+				enum Color { Red, Green }
+				var r: range = (0..10).step(2);
+				for (i in r) { var n: number = i; }
+				for (c in Color) { var v: Color = c; }
+				var s: string = "abc"[0..2];
+				for (x in true) {}
+				var b: bool = 1 in "abc";
+				var bad = 1.."a";
+			`,
+			expected: []string{
+				"[line 7] Type error: Can only iterate over ranges, strings, bytes, enums, sets and tuples, got bool.",
+				"[line 8] Type error: Left operand of 'in' must be a string when searching a string, got number.",
+				"[line 9] Type error: Range bounds must be numbers, got number and string.",
+			},
+		},
+		{
+			name: "sets",
+			input: `
+				var ids: set<number> = #{1, 2} | #{3};
+				ids.add(4);
+				var both: set<number> = ids & #{};
+				ids.add("x");
+				var bad = ids | 1;
+			`,
+			expected: []string{
+				"[line 5] Type error: Argument 1 of type string is not assignable to parameter of type number.",
+				"[line 6] Type error: Operands of '|' must be sets, got set<number> and number.",
+			},
+		},
+		{
+			name: "tuples",
+			input: `
+				fun divmod(a: number, b: number): (number, number) {
+					return a, b;
+				}
+				var q: number, r = divmod(1, 2);
+				var first: number = divmod(1, 2)[0];
+				var s: string, x = divmod(1, 2);
+				var a, b, c = divmod(1, 2);
+				var bad = (1, 2)[2];
+			`,
+			expected: []string{
+				"[line 7] Type error: Cannot assign number to 's' of type string.",
+				"[line 8] Type error: Cannot unpack (number, number) into 3 variables.",
+				"[line 9] Type error: Tuple index 2 is out of bounds for (number, number).",
+			},
+		},
+		{
+			name: "decorators",
+			input: `
+				fun twice(f: fun(number): number): fun(number): number {
+					fun wrapper(x: number): number { return f(f(x)); }
+					return wrapper;
+				}
+				@twice fun inc(x: number): number { return x + 1; }
+				var n: number = inc(1);
+				@twice fun shout(s: string): string { return s; }
+				@n fun bad() {}
+			`,
+			expected: []string{
+				"[line 8] Type error: Cannot decorate fun(string): string with fun(fun(number): number): fun(number): number.",
+				"[line 9] Type error: Decorators must be functions, got number.",
+			},
+		},
+		{
+			name: "comprehensions",
+			input: `
+				var pairs = #{("a", 1), ("b", 2)};
+				var keys: set<string> = #{k for k, v in pairs if v > 1};
+				var bad: set<number> = #{k for k, v in pairs};
+				var worse = #{a for a, b, c in pairs};
+			`,
+			expected: []string{
+				"[line 4] Type error: Cannot assign set<string> to 'bad' of type set<number>.",
+				"[line 5] Type error: Cannot unpack (string, number) into 3 variables.",
+			},
+		},
+		{
+			name: "pipelines",
+			input: `
+				fun inc(x: number): number { return x + 1; }
+				fun add(x: number, y: number): number { return x + y; }
+				var n: number = 1 |> inc |> add(2);
+				var s = "a" |> inc;
+			`,
+			expected: []string{
+				"[line 5] Type error: Argument 1 of type string is not assignable to parameter of type number.",
+			},
+		},
+		{
+			name: "loops",
+			input: `
+				fun first(): number {
+					var i = 0;
+					loop {
+						i = i + 1;
+						while (true) break;
+						if (i > 2) return i;
+					}
+				}
+				fun once(): number {
+					do {
+						return 1;
+					} while (false);
+				}
+				fun maybe(): number {
+					outer: loop {
+						loop { break outer; }
+					}
+				}
+				do {} while ("a" - 1);
+			`,
+			expected: []string{
+				"[line 15] Type error: Function 'maybe' must return a value of type number.",
+				"[line 20] Type error: Operands of '-' must be numbers, got string and number.",
+			},
+		},
+		{
+			name: "with",
+			input: `
+				with (n = 1) {
+					var s: string = n;
+				}
+				with (f = open("x")) {
+					f.read();
+				}
+			`,
+			expected: []string{
+				"[line 3] Type error: Cannot assign number to 's' of type string.",
+			},
+		},
+		{
+			name: "bytes",
+			input: `
+				var b: bytes = b"\x00" + b"a";
+				var n: number = b[0];
+				var slice: bytes = b[0..1];
+				var s: string = b.decode("hex");
+				for (x in b) { var y: number = x; }
+				var bad: string = b[0];
+				b.set("0", 1);
+			`,
+			expected: []string{
+				"[line 7] Type error: Cannot assign number to 'bad' of type string.",
+				"[line 8] Type error: Argument 1 of type string is not assignable to parameter of type number.",
+			},
+		},
+		{
+			name: "optional chaining",
+			input: `
+				var s: string? = nil;
+				var n: number? = s?.length();
+				var c: string? = s?[0];
+				var u: string = s?.upper() ?? "default";
+				var none = nil?.upper().length();
+				var bad: number = s?.length();
+				s.length();
+			`,
+			expected: []string{
+				"[line 7] Type error: Cannot assign number? to 'bad' of type number.",
+				"[line 8] Type error: Value of type string? has no properties.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := lexer.New(tt.input)
+			lex.Lex()
+			program, errs := parser.New(lex.Tokens()).ParseProgram()
+			if errs != nil {
+				t.Errorf("TEST %s non nil parser error %v", tt.name, errs)
+				return
+			}
+			errs = New().Check(program)
+			if len(errs) != len(tt.expected) {
+				t.Errorf("TEST %s Wrong amount of errors: %v, %v", tt.name, errs, tt.expected)
+				return
+			}
+			for i, err := range errs {
+				if err.Error() != tt.expected[i] {
+					t.Errorf("TEST %s Check() got at index %v = %v, want = %v", tt.name, i, err, tt.expected[i])
+				}
+			}
+		})
+	}
+}
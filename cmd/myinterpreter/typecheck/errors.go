@@ -0,0 +1,23 @@
+package typecheck
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/interpreter-starter-go/cmd/myinterpreter/token"
+)
+
+type TypeError struct {
+	t       *token.Token
+	message string
+}
+
+func NewTypeError(t *token.Token, message string) *TypeError {
+	return &TypeError{
+		t:       t,
+		message: message,
+	}
+}
+
+func (e TypeError) Error() string {
+	return fmt.Sprintf("[line %v] Type error: %s", e.t.Line, e.message)
+}
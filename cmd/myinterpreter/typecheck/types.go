@@ -0,0 +1,262 @@
+package typecheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Type interface {
+	String() string
+}
+
+type primitive string
+
+func (p primitive) String() string {
+	return string(p)
+}
+
+var (
+	Any    Type = primitive("any")
+	Number Type = primitive("number")
+	String Type = primitive("string")
+	Bool   Type = primitive("bool")
+	Nil    Type = primitive("nil")
+	Range  Type = primitive("range")
+	Bytes  Type = primitive("bytes")
+)
+
+type Nullable struct {
+	Inner Type
+}
+
+func (n Nullable) String() string {
+	return n.Inner.String() + "?"
+}
+
+type FunctionType struct {
+	Params []Type
+	Return Type
+}
+
+func (f FunctionType) String() string {
+	params := make([]string, len(f.Params))
+	for i, p := range f.Params {
+		params[i] = p.String()
+	}
+	return fmt.Sprintf("fun(%s): %s", strings.Join(params, ", "), f.Return)
+}
+
+type ListType struct {
+	Elem Type
+}
+
+func (l ListType) String() string {
+	return fmt.Sprintf("list<%s>", l.Elem)
+}
+
+type TupleType struct {
+	Elems []Type
+}
+
+func (t TupleType) String() string {
+	elems := make([]string, len(t.Elems))
+	for i, e := range t.Elems {
+		elems[i] = e.String()
+	}
+	if len(elems) == 1 {
+		return fmt.Sprintf("(%s,)", elems[0])
+	}
+	return fmt.Sprintf("(%s)", strings.Join(elems, ", "))
+}
+
+// elem is the type of an element picked out by a non-constant index.
+func (t TupleType) elem() Type {
+	var elem Type = Any
+	for i, e := range t.Elems {
+		if i == 0 {
+			elem = e
+		} else {
+			elem = join(elem, e)
+		}
+	}
+	return elem
+}
+
+type SetType struct {
+	Elem Type
+}
+
+func (s SetType) String() string {
+	return fmt.Sprintf("set<%s>", s.Elem)
+}
+
+// methods mirrors setMethods of the interpreter.
+func (s SetType) methods() map[string]FunctionType {
+	return map[string]FunctionType{
+		"size":     {Params: []Type{}, Return: Number},
+		"add":      {Params: []Type{s.Elem}, Return: Nil},
+		"remove":   {Params: []Type{s.Elem}, Return: Bool},
+		"contains": {Params: []Type{s.Elem}, Return: Bool},
+	}
+}
+
+// EnumType is the type of the variants of an enum.
+type EnumType struct {
+	Name     string
+	Variants []string
+}
+
+func (e *EnumType) String() string {
+	return e.Name
+}
+
+func (e *EnumType) hasVariant(name string) bool {
+	for _, v := range e.Variants {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumNamespace is the type of the enum declaration itself.
+type EnumNamespace struct {
+	Enum *EnumType
+}
+
+func (e EnumNamespace) String() string {
+	return fmt.Sprintf("enum %s", e.Enum.Name)
+}
+
+func nullable(t Type) Type {
+	switch t.(type) {
+	case Nullable:
+		return t
+	}
+	if t == Any || t == Nil {
+		return t
+	}
+	return Nullable{t}
+}
+
+// isAssignable reports whether a value of type from can be stored where a
+// value of type to is expected.
+func isAssignable(from Type, to Type) bool {
+	if from == Any || to == Any {
+		return true
+	}
+	switch t := to.(type) {
+	case Nullable:
+		if from == Nil {
+			return true
+		}
+		if f, ok := from.(Nullable); ok {
+			return isAssignable(f.Inner, t.Inner)
+		}
+		return isAssignable(from, t.Inner)
+	case FunctionType:
+		f, ok := from.(FunctionType)
+		if !ok || len(f.Params) != len(t.Params) {
+			return false
+		}
+		for i := range t.Params {
+			if !isAssignable(t.Params[i], f.Params[i]) {
+				return false
+			}
+		}
+		return isAssignable(f.Return, t.Return)
+	case ListType:
+		f, ok := from.(ListType)
+		return ok && isAssignable(f.Elem, t.Elem) && isAssignable(t.Elem, f.Elem)
+	case SetType:
+		f, ok := from.(SetType)
+		return ok && isAssignable(f.Elem, t.Elem) && isAssignable(t.Elem, f.Elem)
+	case TupleType:
+		f, ok := from.(TupleType)
+		if !ok || len(f.Elems) != len(t.Elems) {
+			return false
+		}
+		for i := range t.Elems {
+			if !isAssignable(f.Elems[i], t.Elems[i]) {
+				return false
+			}
+		}
+		return true
+	case EnumNamespace:
+		f, ok := from.(EnumNamespace)
+		return ok && f.Enum == t.Enum
+	}
+	return from == to
+}
+
+// join returns the type of a value that is either a or b.
+func join(a Type, b Type) Type {
+	if isAssignable(a, b) && a != Any {
+		return b
+	}
+	if isAssignable(b, a) && b != Any {
+		return a
+	}
+	if a == Nil {
+		return nullable(b)
+	}
+	if b == Nil {
+		return nullable(a)
+	}
+	return Any
+}
+
+// builtinMethods mirrors the native method tables of the interpreter.
+var builtinMethods = map[Type]map[string]FunctionType{
+	String: {
+		"length":     {Params: []Type{}, Return: Number},
+		"upper":      {Params: []Type{}, Return: String},
+		"lower":      {Params: []Type{}, Return: String},
+		"trim":       {Params: []Type{}, Return: String},
+		"contains":   {Params: []Type{String}, Return: Bool},
+		"startsWith": {Params: []Type{String}, Return: Bool},
+		"endsWith":   {Params: []Type{String}, Return: Bool},
+		"indexOf":    {Params: []Type{String}, Return: Number},
+		"replace":    {Params: []Type{String, String}, Return: String},
+		"repeat":     {Params: []Type{Number}, Return: String},
+		"substring":  {Params: []Type{Number, Number}, Return: String},
+	},
+	Number: {
+		"abs":   {Params: []Type{}, Return: Number},
+		"floor": {Params: []Type{}, Return: Number},
+		"ceil":  {Params: []Type{}, Return: Number},
+		"round": {Params: []Type{}, Return: Number},
+		"sqrt":  {Params: []Type{}, Return: Number},
+	},
+	Range: {
+		"step": {Params: []Type{Number}, Return: Range},
+	},
+	Bytes: {
+		"length": {Params: []Type{}, Return: Number},
+		"decode": {Params: []Type{String}, Return: String},
+		"set":    {Params: []Type{Number, Number}, Return: Nil},
+		// append takes a number or bytes
+		"append": {Params: []Type{Any}, Return: Nil},
+	},
+}
+
+// elementType returns the type of the values a for-in loop over t yields.
+func elementType(t Type) (Type, bool) {
+	switch t := t.(type) {
+	case EnumNamespace:
+		return t.Enum, true
+	case SetType:
+		return t.Elem, true
+	case TupleType:
+		return t.elem(), true
+	}
+	switch t {
+	case Any:
+		return Any, true
+	case Range, Bytes:
+		return Number, true
+	case String:
+		return String, true
+	}
+	return nil, false
+}